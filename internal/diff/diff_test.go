@@ -0,0 +1,245 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Returns lines "l1\n" to "l<n>\n", with lines of replace replaced by "x<i>\n"
+func lines(n int, replace ...int) string {
+	sb := strings.Builder{}
+	for i := 1; i <= n; i++ {
+		prefix := "l"
+		for _, r := range replace {
+			if r == i {
+				prefix = "x"
+			}
+		}
+		fmt.Fprintf(&sb, "%s%d\n", prefix, i)
+	}
+	return sb.String()
+}
+
+// Expected outputs are the same as of GNU diff -u
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{
+			name: "equal",
+			old:  lines(3),
+			new:  lines(3),
+			want: "",
+		},
+		{
+			name: "insertion",
+			old:  lines(10),
+			new:  lines(5) + "new\n" + strings.TrimPrefix(lines(10), lines(5)),
+			want: `--- old
++++ new
+@@ -3,6 +3,7 @@
+ l3
+ l4
+ l5
++new
+ l6
+ l7
+ l8
+`,
+		},
+		{
+			name: "deletion",
+			old:  lines(10),
+			new:  lines(4) + strings.TrimPrefix(lines(10), lines(5)),
+			want: `--- old
++++ new
+@@ -2,7 +2,6 @@
+ l2
+ l3
+ l4
+-l5
+ l6
+ l7
+ l8
+`,
+		},
+		{
+			name: "change at start",
+			old:  lines(10),
+			new:  lines(10, 1),
+			want: `--- old
++++ new
+@@ -1,4 +1,4 @@
+-l1
++x1
+ l2
+ l3
+ l4
+`,
+		},
+		{
+			name: "change at end",
+			old:  lines(10),
+			new:  lines(10, 10),
+			want: `--- old
++++ new
+@@ -7,4 +7,4 @@
+ l7
+ l8
+ l9
+-l10
++x10
+`,
+		},
+		{
+			name: "missing trailing newline",
+			old:  lines(5),
+			new:  strings.TrimSuffix(lines(5), "\n"),
+			want: `--- old
++++ new
+@@ -2,4 +2,4 @@
+ l2
+ l3
+ l4
+-l5
++l5
+\ No newline at end of file
+`,
+		},
+		{
+			name: "added trailing newline",
+			old:  strings.TrimSuffix(lines(5), "\n"),
+			new:  lines(5),
+			want: `--- old
++++ new
+@@ -2,4 +2,4 @@
+ l2
+ l3
+ l4
+-l5
+\ No newline at end of file
++l5
+`,
+		},
+		{
+			name: "separate hunks",
+			old:  lines(20),
+			new:  lines(20, 2, 18),
+			want: `--- old
++++ new
+@@ -1,5 +1,5 @@
+ l1
+-l2
++x2
+ l3
+ l4
+ l5
+@@ -15,6 +15,6 @@
+ l15
+ l16
+ l17
+-l18
++x18
+ l19
+ l20
+`,
+		},
+		{
+			name: "hunks sharing context",
+			old:  lines(20),
+			new:  lines(20, 5, 12),
+			want: `--- old
++++ new
+@@ -2,14 +2,14 @@
+ l2
+ l3
+ l4
+-l5
++x5
+ l6
+ l7
+ l8
+ l9
+ l10
+ l11
+-l12
++x12
+ l13
+ l14
+ l15
+`,
+		},
+		{
+			name: "hunks with context apart",
+			old:  lines(20),
+			new:  lines(20, 5, 13),
+			want: `--- old
++++ new
+@@ -2,7 +2,7 @@
+ l2
+ l3
+ l4
+-l5
++x5
+ l6
+ l7
+ l8
+@@ -10,7 +10,7 @@
+ l10
+ l11
+ l12
+-l13
++x13
+ l14
+ l15
+ l16
+`,
+		},
+		{
+			name: "new file",
+			old:  "",
+			new:  lines(2),
+			want: `--- old
++++ new
+@@ -0,0 +1,2 @@
++l1
++l2
+`,
+		},
+		{
+			name: "removed file",
+			old:  lines(2),
+			new:  "",
+			want: `--- old
++++ new
+@@ -1,2 +0,0 @@
+-l1
+-l2
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified("old", "new", []byte(tt.old), []byte(tt.new)); got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,12 +20,45 @@ import (
 	"errors"
 	"fmt"
 	"go/types"
+	"slices"
 	"strconv"
 	"strings"
 )
 
 var ErrUnsupportedType = errors.New("unsupported type")
 
+// Byte order of multi-byte values in serialized data.
+type ByteOrder uint8
+
+const (
+	DefaultByteOrder ByteOrder = iota // Not set explicitly, global byte order applies.
+	LittleEndian
+	BigEndian
+)
+
+// Parses byte order from its short name, "le" or "be".
+func ParseByteOrder(s string) (ByteOrder, error) {
+	switch strings.TrimSpace(s) {
+	case "le":
+		return LittleEndian, nil
+	case "be":
+		return BigEndian, nil
+	default:
+		return DefaultByteOrder, fmt.Errorf("unknown byte order '%s', expected 'le' or 'be'", s)
+	}
+}
+
+func (o ByteOrder) String() string {
+	switch o {
+	case LittleEndian:
+		return "le"
+	case BigEndian:
+		return "be"
+	default:
+		return "default"
+	}
+}
+
 type InputStruct struct {
 	name   string
 	typ    *types.Struct
@@ -48,23 +81,98 @@ func (s *InputStruct) SetFields(f []StructField) { s.fields = f }
 
 //
 
+// Directions of [de]serialization, in which the field is skipped.
+type SkipMode uint8
+
+const (
+	SkipRead  SkipMode = 1 << iota // Field bytes are consumed on read, but the field is not assigned.
+	SkipWrite                      // Zeros are written instead of the field value.
+)
+
 type StructField struct {
-	name string
-	typ  FieldType
-	tag  map[string]string
+	name  string
+	typ   FieldType
+	order ByteOrder
+	skip  SkipMode
+	fill  byte   // Byte written instead of the value, when writing is skipped.
+	lenOf string // Name of sequence or string field, whose length this field holds.
+	cond  string // Expression, under which the field is [de]serialized. Empty if the field is unconditional.
+	konst *FieldConst
+	tag   map[string]string
 }
 
-func NewStructField(name string, typ FieldType, tag map[string]string) StructField {
+func NewStructField(name string, typ FieldType, order ByteOrder, tag map[string]string) StructField {
 	return StructField{
-		name: name,
-		typ:  typ,
-		tag:  tag,
+		name:  name,
+		typ:   typ,
+		order: order,
+		tag:   tag,
 	}
 }
 
 func (f StructField) Name() string    { return f.name }
 func (f StructField) Type() FieldType { return f.typ }
 
+func (f StructField) Skip() SkipMode      { return f.skip }
+func (f *StructField) SetSkip(m SkipMode) { f.skip = m }
+
+func (f StructField) FillByte() byte      { return f.fill }
+func (f *StructField) SetFillByte(b byte) { f.fill = b }
+
+func (f StructField) LenOf() string         { return f.lenOf }
+func (f *StructField) SetLenOf(name string) { f.lenOf = name }
+
+func (f StructField) Cond() string         { return f.cond }
+func (f *StructField) SetCond(expr string) { f.cond = expr }
+
+// Constant value of the field, or nil if it has none.
+func (f StructField) Const() *FieldConst      { return f.konst }
+func (f *StructField) SetConst(c *FieldConst) { f.konst = c }
+
+// Blank (`_`) fields are padding, they are never assigned on read, and filled on write.
+func (f StructField) IsPadding() bool { return f.name == "_" }
+
+// Byte order of the field, or def if it is not set explicitly.
+func (f StructField) ByteOrder(def ByteOrder) ByteOrder {
+	if f.order == DefaultByteOrder {
+		return def
+	}
+	return f.order
+}
+
+// Constant value of a field, e.g. a magic number. It is always written instead of the field value,
+// and checked on read.
+type FieldConst struct {
+	expr  string // Go expression of the value
+	value []byte // Serialized value, integers are little-endian
+	isInt bool
+}
+
+// Integer constant. value holds size bytes of integer, in two's complement for signed types.
+func NewIntFieldConst(expr string, value uint64, size int) *FieldConst {
+	c := &FieldConst{expr: expr, value: make([]byte, size), isInt: true}
+	for i := range c.value {
+		c.value[i] = byte(value >> (8 * i))
+	}
+	return c
+}
+
+// Byte array constant.
+func NewBytesFieldConst(expr string, value []byte) *FieldConst {
+	return &FieldConst{expr: expr, value: value}
+}
+
+func (c FieldConst) Expr() string { return c.expr }
+
+// Serialized value, with integers in given byte order.
+func (c FieldConst) Bytes(order ByteOrder) []byte {
+	b := append([]byte{}, c.value...)
+	if c.isInt && order == BigEndian {
+		slices.Reverse(b)
+	}
+	return b
+}
+
 // Type
 
 type FieldType interface {
@@ -116,13 +224,245 @@ func (t SimpleFieldType) Underlying() *SimpleFieldType { return t.underlying }
 func (t SimpleFieldType) IsSequence() bool             { return false }
 
 func (bt SimpleFieldType) IsInteger() bool {
+	name := bt.baseName()
+	return strings.HasPrefix(name, "int") || strings.HasPrefix(name, "uint") || name == "byte"
+}
+
+// Checks if type is a signed integer, or is based on one.
+func (bt SimpleFieldType) IsSigned() bool {
+	return strings.HasPrefix(bt.baseName(), "int")
+}
+
+// Checks if type is float32 or float64, or is based on one of them.
+func (bt SimpleFieldType) IsFloat() bool {
+	return strings.HasPrefix(bt.baseName(), "float")
+}
+
+// Name of the innermost underlying type.
+func (bt SimpleFieldType) baseName() string {
 	tmp := &bt
 	for tmp.underlying != nil {
 		tmp = tmp.underlying
 	}
-	return strings.HasPrefix(tmp.name, "int") || strings.HasPrefix(tmp.name, "uint") || tmp.name == "byte"
+	return tmp.name
+}
+
+// Platform-sized integer
+
+// Integer of platform-dependent size (int, uint or uintptr, or named type based on one of them),
+// serialized as fixed-size integer of the wire type.
+type WireIntFieldType struct {
+	name string // Type of the field.
+	base string // int, uint or uintptr.
+	wire *SimpleFieldType
+}
+
+func NewWireIntFieldType(name, base string, wire *SimpleFieldType) *WireIntFieldType {
+	return &WireIntFieldType{
+		name: name,
+		base: base,
+		wire: wire,
+	}
+}
+
+func (t WireIntFieldType) Name() string           { return t.name }
+func (t WireIntFieldType) Base() string           { return t.base }
+func (t WireIntFieldType) Wire() *SimpleFieldType { return t.wire }
+func (t WireIntFieldType) Size() int              { return t.wire.Size() }
+func (t WireIntFieldType) SizeExpr() string       { return t.wire.SizeExpr() }
+func (t WireIntFieldType) IsSigned() bool         { return t.base == "int" }
+func (t WireIntFieldType) IsInteger() bool        { return true }
+func (t WireIntFieldType) IsSequence() bool       { return false }
+
+// Varint
+
+// Integer, serialized as variable-length unsigned LEB128, like binary.PutUvarint,
+// or, if zigzag is set, as zigzag-encoded signed integer, like binary.PutVarint.
+type VarintFieldType struct {
+	intType   *SimpleFieldType
+	zigzag    bool
+	valueExpr string // Expression to access the value, empty for sequence elements.
+}
+
+func NewVarintFieldType(intType *SimpleFieldType, zigzag bool, valueExpr string) *VarintFieldType {
+	return &VarintFieldType{
+		intType:   intType,
+		zigzag:    zigzag,
+		valueExpr: valueExpr,
+	}
+}
+
+func (t VarintFieldType) Name() string              { return t.intType.Name() }
+func (t VarintFieldType) Size() int                 { return -1 }
+func (t VarintFieldType) SizeExpr() string          { return t.SizeOfExpr(t.valueExpr) }
+func (t VarintFieldType) IntType() *SimpleFieldType { return t.intType }
+func (t VarintFieldType) IsZigzag() bool            { return t.zigzag }
+func (t VarintFieldType) IsInteger() bool           { return true }
+func (t VarintFieldType) IsSequence() bool          { return false }
+
+// Expression of serialized size of integer expression expr.
+func (t VarintFieldType) SizeOfExpr(expr string) string {
+	if t.zigzag {
+		return fmt.Sprintf("simser.VarintSize(int64(%s))", expr)
+	}
+	return fmt.Sprintf("simser.UvarintSize(uint64(%s))", expr)
+}
+
+// Expression of serialized size of all elements of slice expression expr.
+func (t VarintFieldType) SliceSizeOfExpr(expr string) string {
+	if t.zigzag {
+		return fmt.Sprintf("simser.VarintSliceSize(%s)", expr)
+	}
+	return fmt.Sprintf("simser.UvarintSliceSize(%s)", expr)
+}
+
+// Bit fields
+
+// Order of bit fields within their container integer.
+type BitOrder uint8
+
+const (
+	MSBFirst BitOrder = iota // The first field takes the most significant bits.
+	LSBFirst                 // The first field takes the least significant bits.
+)
+
+// Parses bit order from its short name, "msb" or "lsb".
+func ParseBitOrder(s string) (BitOrder, error) {
+	switch strings.TrimSpace(s) {
+	case "msb":
+		return MSBFirst, nil
+	case "lsb":
+		return LSBFirst, nil
+	default:
+		return MSBFirst, fmt.Errorf("unknown bit order '%s', expected 'msb' or 'lsb'", s)
+	}
 }
 
+// Integer or bool field, that takes a number of bits of a container integer, shared with adjacent bit fields.
+type BitField struct {
+	name  string
+	typ   FieldType // *SimpleFieldType integer or *BoolFieldType
+	bits  int
+	shift int // Position of the least significant bit of the field in the container.
+}
+
+func NewBitField(name string, typ FieldType, bits int) BitField {
+	return BitField{
+		name: name,
+		typ:  typ,
+		bits: bits,
+	}
+}
+
+func (f BitField) Name() string    { return f.name }
+func (f BitField) Type() FieldType { return f.typ }
+func (f BitField) Bits() int       { return f.bits }
+func (f BitField) Shift() int      { return f.shift }
+func (f BitField) Mask() uint64    { return 1<<f.bits - 1 }
+
+// Blank (`_`) bit fields are reserved bits, they are never assigned on read, and zeroed on write.
+func (f BitField) IsPadding() bool { return f.name == "_" }
+
+// Adjacent bit fields, packed into an unsigned integer container, which is serialized as a whole.
+type BitGroupFieldType struct {
+	container *SimpleFieldType
+	fields    []BitField
+}
+
+// Fields must take 8, 16, 32 or 64 bits in total.
+func NewBitGroupFieldType(fields []BitField, order BitOrder) *BitGroupFieldType {
+	total := 0
+	for _, f := range fields {
+		total += f.bits
+	}
+	if total != 8 && total != 16 && total != 32 && total != 64 {
+		panic(fmt.Sprintf("bit fields take %d bits, not 8, 16, 32 or 64. This should be caught earlier. Please, file a bug to the repo.",
+			total))
+	}
+
+	t := &BitGroupFieldType{
+		container: NewSimpleFieldType(fmt.Sprintf("uint%d", total), total/8, nil),
+		fields:    append([]BitField{}, fields...),
+	}
+	used := 0
+	for i := range t.fields {
+		if order == MSBFirst {
+			t.fields[i].shift = total - used - t.fields[i].bits
+		} else {
+			t.fields[i].shift = used
+		}
+		used += t.fields[i].bits
+	}
+	return t
+}
+
+func (t BitGroupFieldType) Name() string                { return t.container.Name() }
+func (t BitGroupFieldType) Size() int                   { return t.container.Size() }
+func (t BitGroupFieldType) SizeExpr() string            { return t.container.SizeExpr() }
+func (t BitGroupFieldType) Container() *SimpleFieldType { return t.container }
+func (t BitGroupFieldType) Field(idx int) BitField      { return t.fields[idx] }
+func (t BitGroupFieldType) FieldCount() int             { return len(t.fields) }
+func (t BitGroupFieldType) IsInteger() bool             { return false }
+func (t BitGroupFieldType) IsSequence() bool            { return false }
+
+// Codec
+
+// Field of any type, [de]serialized by user-provided functions of the package:
+// <codec>Read(r []byte) (T, int, error) and <codec>Write(dst []byte, v T) []byte.
+// Read function returns the number of bytes consumed. If r is too short, it returns io.ErrUnexpectedEOF
+// and the number of bytes it needs, greater than len(r), which are read from io.Reader at once before the next call.
+type CodecFieldType struct {
+	name      string
+	codec     string
+	valueExpr string
+	maxLen    int // Max size of encoded value, read from io.Reader.
+}
+
+func NewCodecFieldType(name, codec, valueExpr string, maxLen int) *CodecFieldType {
+	return &CodecFieldType{
+		name:      name,
+		codec:     codec,
+		valueExpr: valueExpr,
+		maxLen:    maxLen,
+	}
+}
+
+func (t CodecFieldType) Name() string     { return t.name }
+func (t CodecFieldType) Codec() string    { return t.codec }
+func (t CodecFieldType) ReadFn() string   { return t.codec + "Read" }
+func (t CodecFieldType) WriteFn() string  { return t.codec + "Write" }
+func (t CodecFieldType) MaxLen() int      { return t.maxLen }
+func (t CodecFieldType) Size() int        { return -1 }
+func (t CodecFieldType) IsInteger() bool  { return false }
+func (t CodecFieldType) IsSequence() bool { return false }
+
+// Size is known only after encoding the value.
+func (t CodecFieldType) SizeExpr() string {
+	return fmt.Sprintf("len(%s(nil, %s))", t.WriteFn(), t.valueExpr)
+}
+
+// Bool
+
+// Boolean, serialized as a single byte, 1 for true and 0 for false.
+type BoolFieldType struct {
+	name   string
+	strict bool // If set, only 0 and 1 are valid values on read. Otherwise, any non-zero value is true.
+}
+
+func NewBoolFieldType(name string, strict bool) *BoolFieldType {
+	return &BoolFieldType{
+		name:   name,
+		strict: strict,
+	}
+}
+
+func (t BoolFieldType) Name() string     { return t.name }
+func (t BoolFieldType) Size() int        { return 1 }
+func (t BoolFieldType) SizeExpr() string { return "1" }
+func (t BoolFieldType) IsStrict() bool   { return t.strict }
+func (t BoolFieldType) IsInteger() bool  { return false }
+func (t BoolFieldType) IsSequence() bool { return false }
+
 // Array
 
 type ArrayFieldType struct {
@@ -141,7 +481,7 @@ func NewArrayFieldType(length int, el FieldType) *ArrayFieldType {
 	}
 }
 
-func (t ArrayFieldType) Name() string { return fmt.Sprintf("[]%s", t.elType.Name()) }
+func (t ArrayFieldType) Name() string { return fmt.Sprintf("[%d]%s", t.length, t.elType.Name()) }
 
 func (t ArrayFieldType) Size() int {
 	if !IsFixedSize(t.elType.Size()) {
@@ -165,12 +505,14 @@ func (t ArrayFieldType) IsSequence() bool  { return true }
 
 type SliceFieldType struct {
 	lenExpr string // expression used to calculate length.
+	maxLen  int    // max length on read, 0 if not limited.
 	elType  FieldType
 }
 
-func NewSliceFieldType(lenExpr string, el FieldType) *SliceFieldType {
+func NewSliceFieldType(lenExpr string, maxLen int, el FieldType) *SliceFieldType {
 	return &SliceFieldType{
 		lenExpr: strings.TrimSpace(lenExpr),
+		maxLen:  maxLen,
 		elType:  el,
 	}
 }
@@ -182,6 +524,143 @@ func (t SliceFieldType) SizeExpr() string {
 	return fmt.Sprintf("%s * %s", ParenthesizeIntExpr(t.elType.SizeExpr()), ParenthesizeIntExpr(t.lenExpr))
 }
 func (t SliceFieldType) LenExpr() string   { return t.lenExpr }
+func (t SliceFieldType) MaxLen() int       { return t.maxLen }
 func (t SliceFieldType) ElType() FieldType { return t.elType }
 func (t SliceFieldType) IsInteger() bool   { return false }
 func (t SliceFieldType) IsSequence() bool  { return true }
+
+// String
+
+// The way string length is determined in serialized data.
+type StringEncoding uint8
+
+const (
+	StringLenExpr       StringEncoding = iota // Length is set by expression, like for slices.
+	StringPrefixed                            // Length is serialized before string bytes, as an integer of fixed size.
+	StringFixed                               // Zero-padded buffer of fixed size.
+	StringNulTerminated                       // String bytes are followed by NUL byte.
+)
+
+type StringFieldType struct {
+	name     string
+	encoding StringEncoding
+	lenExpr  string    // Length expression for StringLenExpr, expression of actual value length otherwise.
+	prefix   FieldType // Type of length prefix, for StringPrefixed. Either fixed-size integer or varint.
+	size     int       // Buffer size for StringFixed, max length (without NUL) otherwise, 0 if not limited.
+}
+
+// maxLen is max length on read, 0 if not limited.
+func NewLenExprStringFieldType(name string, lenExpr string, maxLen int) *StringFieldType {
+	return &StringFieldType{
+		name:     name,
+		encoding: StringLenExpr,
+		lenExpr:  strings.TrimSpace(lenExpr),
+		size:     maxLen,
+	}
+}
+
+// valueExpr is an expression to access the field value.
+// maxLen is max length on read, 0 if not limited.
+func NewPrefixedStringFieldType(name string, valueExpr string, prefix FieldType, maxLen int) *StringFieldType {
+	return &StringFieldType{
+		name:     name,
+		encoding: StringPrefixed,
+		lenExpr:  fmt.Sprintf("len(%s)", valueExpr),
+		prefix:   prefix,
+		size:     maxLen,
+	}
+}
+
+func NewFixedStringFieldType(name string, size int) *StringFieldType {
+	if size < 1 {
+		panic(fmt.Sprintf("fixed string size < 1. This should be caught earlier. Please, file a bug to the repo. Size %d",
+			size))
+	}
+	return &StringFieldType{
+		name:     name,
+		encoding: StringFixed,
+		lenExpr:  strconv.Itoa(size),
+		size:     size,
+	}
+}
+
+// valueExpr is an expression to access the field value.
+func NewNulTerminatedStringFieldType(name string, valueExpr string, maxLen int) *StringFieldType {
+	return &StringFieldType{
+		name:     name,
+		encoding: StringNulTerminated,
+		lenExpr:  fmt.Sprintf("len(%s)", valueExpr),
+		size:     maxLen,
+	}
+}
+
+func (t StringFieldType) Name() string { return t.name }
+
+func (t StringFieldType) Size() int {
+	if t.encoding == StringFixed {
+		return t.size
+	}
+	return -1
+}
+func (t StringFieldType) SizeExpr() string {
+	switch t.encoding {
+	case StringPrefixed:
+		if vt, ok := t.prefix.(*VarintFieldType); ok {
+			return fmt.Sprintf("%s + %s", vt.SizeOfExpr(t.lenExpr), t.lenExpr)
+		}
+		return fmt.Sprintf("%d + %s", t.prefix.Size(), t.lenExpr)
+	case StringNulTerminated:
+		return fmt.Sprintf("%s + 1", t.lenExpr)
+	default:
+		return t.lenExpr
+	}
+}
+func (t StringFieldType) Encoding() StringEncoding { return t.encoding }
+func (t StringFieldType) LenExpr() string          { return t.lenExpr }
+func (t StringFieldType) Prefix() FieldType        { return t.prefix }
+func (t StringFieldType) BufSize() int             { return t.size }
+func (t StringFieldType) MaxLen() int              { return t.size }
+func (t StringFieldType) IsInteger() bool          { return false }
+func (t StringFieldType) IsSequence() bool         { return false }
+
+// Struct
+
+// Nested struct, whose fields are [de]serialized in place, one after another.
+type StructFieldType struct {
+	name   string // Type name, or type literal for anonymous structs.
+	fields []StructField
+}
+
+func NewStructFieldType(name string, fields []StructField) *StructFieldType {
+	return &StructFieldType{
+		name:   name,
+		fields: fields,
+	}
+}
+
+func (t StructFieldType) Name() string { return t.name }
+
+func (t StructFieldType) Size() int {
+	size := 0
+	for _, f := range t.fields {
+		if !IsFixedSize(f) {
+			return -1
+		}
+		size += f.Type().Size()
+	}
+	return size
+}
+func (t StructFieldType) SizeExpr() string {
+	if IsFixedSize(t) {
+		return strconv.Itoa(t.Size())
+	}
+	exprs := make([]string, len(t.fields))
+	for i, f := range t.fields {
+		exprs[i] = ParenthesizeIntExpr(f.Type().SizeExpr())
+	}
+	return strings.Join(exprs, " + ")
+}
+func (t StructFieldType) Field(idx int) StructField { return t.fields[idx] }
+func (t StructFieldType) FieldCount() int           { return len(t.fields) }
+func (t StructFieldType) IsInteger() bool           { return false }
+func (t StructFieldType) IsSequence() bool          { return false }
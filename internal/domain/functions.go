@@ -48,17 +48,24 @@ func ParenthesizeIntExpr(expr string) string {
 // Returns true if total argument's size can be interpreted as known at declaration time.
 // E.g. (primitives, arrays). NOT slices.
 func IsFixedSize[
-	T StructField | SimpleFieldType | ArrayFieldType | SliceFieldType | int](a T) bool {
+	T StructField | SimpleFieldType | BoolFieldType | ArrayFieldType | SliceFieldType | StringFieldType | StructFieldType | int](a T) bool {
 
 	switch arg := any(a).(type) {
 	case StructField:
-		return arg.Type().Size() >= 0
+		// Conditional field may be absent
+		return arg.Cond() == "" && arg.Type().Size() >= 0
 	case SimpleFieldType:
 		return arg.Size() >= 0
+	case BoolFieldType:
+		return arg.Size() >= 0
 	case ArrayFieldType:
 		return arg.Size() >= 0
 	case SliceFieldType:
 		return arg.Size() >= 0
+	case StringFieldType:
+		return arg.Size() >= 0
+	case StructFieldType:
+		return arg.Size() >= 0
 	case int:
 		return arg >= 0
 	default:
@@ -0,0 +1,160 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"testing"
+)
+
+// Same as encoding.BinaryAppender, which is not available before Go 1.24
+var _ interface {
+	AppendBinary(b []byte) ([]byte, error)
+} = (*Fixed)(nil)
+
+var fixed = Fixed{Version: 2, Flags: 0xA, Skipped: 7, Pos: [3]float32{1, -2.5, 3}, ID: -42, Ok: true}
+
+// Fails if f allocates
+func checkNoAllocs(tb testing.TB, name string, f func()) {
+	tb.Helper()
+	if allocs := testing.AllocsPerRun(100, f); allocs > 0 {
+		tb.Fatalf("%s: %v allocs/op, want 0", name, allocs)
+	}
+}
+
+func decodeFixed(tb testing.TB, src []byte) func() {
+	var o Fixed
+	return func() {
+		if _, err := o.DecodeFrom(src); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+func marshalFixed(tb testing.TB, dst []byte) func() {
+	return func() {
+		if _, err := fixed.MarshalTo(dst); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+func appendFixed(tb testing.TB, dst []byte) func() {
+	return func() {
+		if _, err := fixed.AppendBinary(dst[:0]); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+func TestFixedRoundTrip(t *testing.T) {
+	data, err := fixed.AppendBinary(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != FixedEncodedSize {
+		t.Fatalf("len(AppendBinary()) = %d, want %d", len(data), FixedEncodedSize)
+	}
+	var out Fixed
+	n, err := out.DecodeFrom(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(data) {
+		t.Fatalf("DecodeFrom() = %d, want %d", n, len(data))
+	}
+	want := fixed
+	want.Magic, want.Skipped = 0x44584946, 0
+	if out != want {
+		t.Fatalf("DecodeFrom() = %+v, want %+v", out, want)
+	}
+
+	var s Skipped
+	if n, err := s.DecodeFrom(make([]byte, SkippedEncodedSize)); err != nil || n != SkippedEncodedSize {
+		t.Fatalf("Skipped.DecodeFrom() = %d, %v", n, err)
+	}
+}
+
+func TestFixedNoAllocs(t *testing.T) {
+	buf := make([]byte, FixedEncodedSize)
+	if _, err := fixed.MarshalTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	checkNoAllocs(t, "DecodeFrom", decodeFixed(t, buf))
+	checkNoAllocs(t, "UnmarshalBinary", func() {
+		var o Fixed
+		if err := o.UnmarshalBinary(buf); err != nil {
+			t.Fatal(err)
+		}
+	})
+	checkNoAllocs(t, "MarshalTo", marshalFixed(t, buf))
+	checkNoAllocs(t, "AppendBinary", appendFixed(t, buf))
+}
+
+func benchmarkFixed(b *testing.B, name string, f func()) {
+	checkNoAllocs(b, name, f)
+	b.SetBytes(FixedEncodedSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f()
+	}
+}
+
+func BenchmarkFixedDecodeFrom(b *testing.B) {
+	buf, err := fixed.AppendBinary(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkFixed(b, "DecodeFrom", decodeFixed(b, buf))
+}
+
+func BenchmarkFixedMarshalTo(b *testing.B) {
+	benchmarkFixed(b, "MarshalTo", marshalFixed(b, make([]byte, FixedEncodedSize)))
+}
+
+func BenchmarkFixedAppendBinary(b *testing.B) {
+	benchmarkFixed(b, "AppendBinary", appendFixed(b, make([]byte, 0, FixedEncodedSize)))
+}
+
+func TestAppendBinaryGrowth(t *testing.T) {
+	const count = 1000
+	data, err := fixed.AppendBinary(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Plain append of the same data is the baseline
+	want := testing.AllocsPerRun(1, func() {
+		var dst []byte
+		for i := 0; i < count; i++ {
+			dst = append(dst, data...)
+		}
+	})
+	var dst []byte
+	allocs := testing.AllocsPerRun(1, func() {
+		dst = dst[:0:0]
+		for i := 0; i < count; i++ {
+			if dst, err = fixed.AppendBinary(dst); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	if allocs > want {
+		t.Fatalf("%d appends: %v allocs, want at most %v", count, allocs, want)
+	}
+	if len(dst) != count*len(data) {
+		t.Fatalf("len(dst) = %d, want %d", len(dst), count*len(data))
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import "testing"
+
+func TestPackedBits(t *testing.T) {
+	if PackedEncodedSize != 6 {
+		t.Fatalf("PackedEncodedSize = %d, want 6", PackedEncodedSize)
+	}
+	testRoundTrips(t, []roundTrip{
+		{
+			name: "values",
+			in:   &Packed{A: 0x1, B: 0x2, C: 0x3, D: 0xAA, E: 0x1234, F: 0x56},
+			data: []byte{
+				0x21, 0x03, // A, B, C: 0x0321, little-endian, from the least significant bit
+				0xAA, 0x12, 0x34, 0x56, // D, E, F: 0xAA123456, big-endian, from the most significant bit
+			},
+			out: &Packed{},
+		},
+		{
+			name: "max",
+			in:   &Packed{A: 0xF, B: 0xF, C: 0xFF, D: 0xFF, E: 0xFFFF, F: 0xFF},
+			data: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+			out:  &Packed{},
+		},
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import "testing"
+
+func TestBools(t *testing.T) {
+	testRoundTrips(t, []roundTrip{
+		{
+			name: "true",
+			in:   &Bools{true, true, true, true},
+			data: []byte{1, 1, 1, 1},
+			out:  &Bools{},
+		},
+		{
+			name: "false",
+			in:   &Bools{},
+			data: []byte{0, 0, 0, 0},
+			out:  &Bools{},
+		},
+	})
+
+	// Any non-zero value is true, unless strict
+	for _, r := range readers {
+		out := &Bools{}
+		if err := r.read(out, []byte{2, 0xFF, 1, 0}); err != nil {
+			t.Fatalf("%s(): %v", r.name, err)
+		}
+		if want := (Bools{true, true, true, false}); *out != want {
+			t.Fatalf("%s() = %+v, want %+v", r.name, *out, want)
+		}
+	}
+
+	testReadErrors(t, []readError{
+		{
+			name: "strict",
+			data: []byte{0, 0, 2, 0},
+			out:  &Bools{},
+			err:  "Bools.S at offset 2: invalid bool value 2",
+		},
+		{
+			name: "strict named",
+			data: []byte{0, 0, 1, 0xFF},
+			out:  &Bools{},
+			err:  "Bools.T at offset 3: invalid bool value 255",
+		},
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import "io"
+
+// Number of wordsRead and wordsWrite calls, for tests
+var wordsReadCalls, wordsWriteCalls int
+
+// Reads list of words, prefixed with their count. Each word is prefixed with its length.
+func wordsRead(r []byte) ([]string, int, error) {
+	wordsReadCalls++
+	if len(r) < 1 {
+		return nil, 1, io.ErrUnexpectedEOF
+	}
+	words, p := make([]string, 0, r[0]), 1
+	for i := 0; i < cap(words); i++ {
+		if len(r) < p+1 {
+			return nil, p + 1, io.ErrUnexpectedEOF
+		}
+		end := p + 1 + int(r[p])
+		if len(r) < end {
+			return nil, end, io.ErrUnexpectedEOF
+		}
+		words = append(words, string(r[p+1:end]))
+		p = end
+	}
+	return words, p, nil
+}
+
+func wordsWrite(dst []byte, v []string) []byte {
+	wordsWriteCalls++
+	dst = append(dst, byte(len(v)))
+	for _, w := range v {
+		dst = append(dst, byte(len(w)))
+		dst = append(dst, w...)
+	}
+	return dst
+}
+
+// Reads little-endian uint16, without reporting the number of bytes it needs
+func plainRead(r []byte) (uint16, int, error) {
+	if len(r) < 2 {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	return uint16(r[0]) | uint16(r[1])<<8, 2, nil
+}
+
+func plainWrite(dst []byte, v uint16) []byte {
+	return append(dst, byte(v), byte(v>>8))
+}
@@ -0,0 +1,37 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import "testing"
+
+// Writer evaluates conditions with lengths it writes, not with stale length fields
+func TestCondOnLength(t *testing.T) {
+	testRoundTrips(t, []roundTrip{
+		{
+			name: "stale zero",
+			in:   &Mix{N: 0, Names: []uint16{1, 2}, S: "ab"},
+			data: []byte{0x02, 0x01, 0x00, 0x02, 0x00, 0x02, 'a', 'b'},
+			out:  &Mix{},
+			want: &Mix{N: 2, Names: []uint16{1, 2}, S: "ab"},
+		},
+		{
+			name: "stale non-zero",
+			in:   &Mix{N: 3, S: "ab"},
+			data: []byte{0x00},
+			out:  &Mix{},
+			want: &Mix{Names: []uint16{}},
+		},
+	})
+}
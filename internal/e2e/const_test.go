@@ -0,0 +1,79 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/amanofbits/simser/pkg/simser"
+)
+
+var constsData = []byte{'R', 'I', 'F', 'F', 0x01, 0x02, 'W', 'A', 'V', 'E', 0x05, 0x00, 0x00, 0x00}
+
+func TestConsts(t *testing.T) {
+	testRoundTrips(t, []roundTrip{
+		{
+			name: "written regardless of value",
+			in:   &Consts{Magic: [4]byte{'X'}, Version: 7, Size: 5},
+			data: constsData,
+			out:  &Consts{},
+			want: &Consts{Magic: [4]byte{'R', 'I', 'F', 'F'}, Version: 0x0102, Tag: [4]byte{'W', 'A', 'V', 'E'}, Size: 5},
+		},
+	})
+
+	// Returns constsData with bytes at offset replaced
+	withBytes := func(offset int, b ...byte) []byte {
+		data := append([]byte{}, constsData...)
+		copy(data[offset:], b)
+		return data
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want simser.ConstMismatchError
+	}{
+		{
+			name: "byte array",
+			data: withBytes(3, 'X'),
+			want: simser.ConstMismatchError{Type: "Consts", Field: "Magic", Offset: 0,
+				Expected: [4]byte{'R', 'I', 'F', 'F'}, Actual: [4]byte{'R', 'I', 'F', 'X'}},
+		},
+		{
+			name: "big-endian integer",
+			data: withBytes(4, 0x02, 0x01),
+			want: simser.ConstMismatchError{Type: "Consts", Field: "Version", Offset: 4,
+				Expected: uint16(0x0102), Actual: uint16(0x0201)},
+		},
+		{
+			name: "big-endian byte array",
+			data: withBytes(6, 'E', 'V', 'A', 'W'),
+			want: simser.ConstMismatchError{Type: "Consts", Field: "Tag", Offset: 6,
+				Expected: [4]byte{'W', 'A', 'V', 'E'}, Actual: [4]byte{'E', 'V', 'A', 'W'}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, r := range readers {
+				err := r.read(&Consts{}, tt.data)
+				var cErr *simser.ConstMismatchError
+				if !errors.As(err, &cErr) || !reflect.DeepEqual(*cErr, tt.want) {
+					t.Fatalf("%s() error = %#v, want %#v", r.name, err, &tt.want)
+				}
+			}
+		})
+	}
+}
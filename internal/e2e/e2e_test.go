@@ -0,0 +1,192 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/amanofbits/simser/pkg/simser"
+)
+
+func TestCountedRoundTrip(t *testing.T) {
+	in := Counted{Items: []uint32{1, 2, 0xDEADBEEF}}
+	data, err := in.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{3, 0, 1, 0, 0, 0, 2, 0, 0, 0, 0xEF, 0xBE, 0xAD, 0xDE}
+	if !bytes.Equal(data, want) {
+		t.Fatalf("MarshalBinary() = % x, want % x", data, want)
+	}
+
+	var out Counted
+	if err := out.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	in.Count = 3
+	if !reflect.DeepEqual(out, in) {
+		t.Fatalf("UnmarshalBinary() = %+v, want %+v", out, in)
+	}
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	for _, in := range []Message{
+		{Flags: 1, Seq: 1 << 40, Delta: -3, Topic: "news", Body: "hello", Score: 0.5},
+		{Flags: 2, Seq: 7, Delta: 1000, Topic: "", Body: ""},
+	} {
+		data, err := in.AppendBinary(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) != in.EncodedSize() {
+			t.Fatalf("len(AppendBinary()) = %d, want %d", len(data), in.EncodedSize())
+		}
+		var out Message
+		if _, err := out.ReadFrom(bytes.NewReader(data)); err != nil {
+			t.Fatal(err)
+		}
+		if out != in {
+			t.Fatalf("ReadFrom() = %+v, want %+v", out, in)
+		}
+	}
+}
+
+func TestCodedFromReader(t *testing.T) {
+	in := Coded{Kind: 1, Words: []string{"a", strings.Repeat("b", 200), strings.Repeat("c", 255)}, Tail: 0xBEEF}
+	data, err := in.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := bytes.NewReader(append(data, 0xFF))
+
+	wordsReadCalls = 0
+	var out Coded
+	n, err := out.LoadFrom(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(data) || r.Len() != 1 {
+		t.Fatalf("LoadFrom() = %d, %d bytes left, want %d, 1", n, r.Len(), len(data))
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Fatalf("LoadFrom() = %+v, want %+v", out, in)
+	}
+	// Count, then length and bytes of each word are read at once
+	if want := 2 + 2*len(in.Words); wordsReadCalls != want {
+		t.Fatalf("wordsRead is called %d times, want %d", wordsReadCalls, want)
+	}
+
+	in.Words = append(in.Words, strings.Repeat("d", 255), strings.Repeat("e", 255), strings.Repeat("f", 255))
+	if data, err = in.MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+	var fe *simser.FieldError
+	if _, err := out.LoadFrom(bytes.NewReader(data)); !errors.As(err, &fe) || fe.Field != "Words" {
+		t.Fatalf("LoadFrom() error = %v, want *simser.FieldError for Words", err)
+	}
+}
+
+// Codec read function reports the bytes it needs with io.ErrUnexpectedEOF
+func TestCodecNeededBytes(t *testing.T) {
+	testReadErrors(t, []readError{
+		{
+			name: "truncated word",
+			data: []byte{0x01, 0x02, 0x03, 'a', 'b'},
+			out:  &Coded{},
+			err:  "Coded.Words at offset 1: unexpected EOF",
+		},
+		{
+			name: "truncated count",
+			data: []byte{0x01},
+			out:  &Coded{},
+			err:  "Coded.Words at offset 1: unexpected EOF",
+		},
+	})
+
+	// Function, which doesn't report them, works only with byte slice source
+	data := []byte{0x34, 0x12}
+	out := Plain{}
+	want := "Plain.V at offset 0: plainRead returned io.ErrUnexpectedEOF, asking for 0 bytes, not more than 0 it got"
+	if _, err := out.ReadFrom(bytes.NewReader(data)); err == nil || err.Error() != want {
+		t.Fatalf("ReadFrom() error = %v, want %s", err, want)
+	}
+	if _, err := out.DecodeFrom(data); err != nil || out.V != 0x1234 {
+		t.Fatalf("DecodeFrom() = %#x, %v, want 0x1234", out.V, err)
+	}
+	if _, err := out.DecodeFrom(data[:1]); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("DecodeFrom() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+	out = Plain{}
+	if err := out.UnmarshalBinary(data); err != nil || out.V != 0x1234 {
+		t.Fatalf("UnmarshalBinary() = %#x, %v, want 0x1234", out.V, err)
+	}
+}
+
+func TestUnmarshalBinaryLeftover(t *testing.T) {
+	data := []byte{0x01, 0x00, 0x02, 0x00, 0x00, 0x00, 0xFF, 0xFF}
+	var out Counted
+	want := "Counted: 2 bytes left after unmarshaling"
+	if err := out.UnmarshalBinary(data); err == nil || err.Error() != want {
+		t.Fatalf("UnmarshalBinary() error = %v, want %s", err, want)
+	}
+	if err := out.UnmarshalBinary(data[:6]); err != nil || !reflect.DeepEqual(out.Items, []uint32{2}) {
+		t.Fatalf("UnmarshalBinary() = %+v, %v, want Items [2]", out, err)
+	}
+}
+
+// Codec value is encoded once to get the size, and once to write it
+func TestCodecWriteCalls(t *testing.T) {
+	in := Coded{Kind: 1, Words: []string{"ab", "c"}, Tail: 2}
+	size := in.EncodedSize()
+	tests := []struct {
+		name  string
+		write func() error
+		calls int
+	}{
+		{"MarshalTo", func() error { _, err := in.MarshalTo(make([]byte, size)); return err }, 1},
+		{"AppendBinary", func() error { _, err := in.AppendBinary(nil); return err }, 2},
+		{"MarshalBinary", func() error { _, err := in.MarshalBinary(); return err }, 2},
+		{"SaveTo", func() error { _, err := in.SaveTo(io.Discard); return err }, 2},
+		{"WriteTo", func() error { _, err := in.WriteTo(io.Discard); return err }, 2},
+	}
+	for _, tt := range tests {
+		wordsWriteCalls = 0
+		if err := tt.write(); err != nil {
+			t.Fatalf("%s(): %v", tt.name, err)
+		}
+		if wordsWriteCalls != tt.calls {
+			t.Errorf("%s() calls wordsWrite %d times, want %d", tt.name, wordsWriteCalls, tt.calls)
+		}
+	}
+}
+
+// Size of variable-size struct is checked while writing, and bytes past len(dst) are not changed
+func TestMarshalToShortBuffer(t *testing.T) {
+	in := Coded{Kind: 1, Words: []string{"ab", "c"}, Tail: 2}
+	for short := 0; short < in.EncodedSize(); short++ {
+		buf := bytes.Repeat([]byte{0xAA}, in.EncodedSize())
+		if n, err := in.MarshalTo(buf[:short]); n != 0 || err != io.ErrShortBuffer {
+			t.Fatalf("MarshalTo(%d bytes) = %d, %v, want 0, %v", short, n, err, io.ErrShortBuffer)
+		}
+		if !bytes.Equal(buf[short:], bytes.Repeat([]byte{0xAA}, len(buf)-short)) {
+			t.Fatalf("MarshalTo(%d bytes) changed bytes past the end: % x", short, buf)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filemode holds types, generated in file mode, for end-to-end tests of simser.
+package filemode
+
+//go:generate go run github.com/amanofbits/simser -types=Point
+
+type Point struct {
+	X, Y int32
+	Name string `simser:"prefix=u8"`
+}
@@ -0,0 +1,98 @@
+// Code generated by "simser -types=Point"; DO NOT EDIT.
+
+package filemode
+
+import (
+	"fmt"
+	"github.com/amanofbits/simser/pkg/simser"
+	"io"
+)
+
+// EncodedSize returns the size of serialized o, in bytes.
+func (o *Point) EncodedSize() int {
+	return 8 + (1 + len(o.Name))
+}
+
+func (o *Point) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Point", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// X
+	errField, errOffset = "X", n
+	p, toRead = 0, 8
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.X = int32(b[p]) | int32(b[p+1])<<8 | int32(b[p+2])<<16 | int32(b[p+3])<<24
+	p += 4
+
+	// Y
+	errField, errOffset = "Y", n-toRead+p
+	o.Y = int32(b[p]) | int32(b[p+1])<<8 | int32(b[p+2])<<16 | int32(b[p+3])<<24
+	p += 4
+
+	// Name
+	errField, errOffset = "Name", n
+	p, toRead = 0, 1
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	{
+		strLen := uint8(b[p])
+		p += 1
+		p, toRead = 0, int(strLen)
+	}
+	if toRead < 0 {
+		return n, &simser.LengthError{Length: toRead, Max: -1}
+	}
+	b, nRead, err = simser.ReadFull(r, b, toRead)
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Name = string(b[:toRead])
+	p += toRead
+
+	return n, err
+}
+
+func (o *Point) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, 0, o.EncodedSize())
+
+	// X
+	b = append(b, byte(o.X), byte(o.X>>8), byte(o.X>>16), byte(o.X>>24))
+
+	// Y
+	b = append(b, byte(o.Y), byte(o.Y>>8), byte(o.Y>>16), byte(o.Y>>24))
+
+	// Name
+	if uint64(len(o.Name)) > 0xFF {
+		return 0, fmt.Errorf("Name: string length %d overflows uint8 length prefix", len(o.Name))
+	}
+	b = append(b, byte(uint8(len(o.Name))))
+	b = append(b, o.Name...)
+	return w.Write(b)
+}
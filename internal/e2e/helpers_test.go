@@ -0,0 +1,151 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"bytes"
+	"encoding"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// Methods, generated with -bytes and -interfaces=std
+type encoder interface {
+	encoding.BinaryMarshaler
+	io.WriterTo
+	AppendBinary(dst []byte) ([]byte, error)
+}
+
+type decoder interface {
+	encoding.BinaryUnmarshaler
+	io.ReaderFrom
+	DecodeFrom(src []byte) (n int, err error)
+}
+
+// Reading functions of a decoder, from io.Reader and from byte slice
+var readers = []struct {
+	name string
+	read func(d decoder, data []byte) error
+}{
+	{"ReadFrom", func(d decoder, data []byte) error {
+		_, err := d.ReadFrom(bytes.NewReader(data))
+		return err
+	}},
+	{"DecodeFrom", func(d decoder, data []byte) error {
+		_, err := d.DecodeFrom(data)
+		return err
+	}},
+	{"UnmarshalBinary", func(d decoder, data []byte) error {
+		return d.UnmarshalBinary(data)
+	}},
+}
+
+// Returns new zero value of the type d points to
+func newDecoder(d decoder) decoder {
+	return reflect.New(reflect.TypeOf(d).Elem()).Interface().(decoder)
+}
+
+// Value and its serialized form, checked in both directions
+type roundTrip struct {
+	name string
+	in   encoder
+	data []byte  // Expected serialized data
+	out  decoder // Pointer to value of the type to read data into
+	want decoder // Expected value after reading, or in if nil
+}
+
+func testRoundTrips(t *testing.T, tests []roundTrip) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.in.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary(): %v", err)
+			}
+			if !bytes.Equal(data, tt.data) {
+				t.Fatalf("MarshalBinary() = % x, want % x", data, tt.data)
+			}
+			// Fixed-size structs have size constant instead
+			if sized, ok := tt.in.(interface{ EncodedSize() int }); ok && sized.EncodedSize() != len(data) {
+				t.Fatalf("EncodedSize() = %d, want %d", sized.EncodedSize(), len(data))
+			}
+			if appended, err := tt.in.AppendBinary([]byte{0xAA}); err != nil || !bytes.Equal(appended, append([]byte{0xAA}, data...)) {
+				t.Fatalf("AppendBinary(aa) = % x, %v, want aa % x", appended, err, data)
+			}
+			buf := bytes.Buffer{}
+			if _, err := tt.in.WriteTo(&buf); err != nil || !bytes.Equal(buf.Bytes(), data) {
+				t.Fatalf("WriteTo() = % x, %v, want % x", buf.Bytes(), err, data)
+			}
+
+			var want any = tt.in
+			if tt.want != nil {
+				want = tt.want
+			}
+			for _, r := range readers {
+				out := newDecoder(tt.out)
+				if err := r.read(out, data); err != nil {
+					t.Fatalf("%s(): %v", r.name, err)
+				}
+				if !reflect.DeepEqual(out, want) {
+					t.Fatalf("%s() = %+v, want %+v", r.name, out, want)
+				}
+			}
+		})
+	}
+}
+
+// Data, that fails to be read
+type readError struct {
+	name string
+	data []byte
+	out  decoder // Pointer to value of the type to read data into
+	err  string  // Expected error message
+}
+
+func testReadErrors(t *testing.T, tests []readError) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, r := range readers {
+				err := r.read(newDecoder(tt.out), tt.data)
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("%s() error = %v, want %s", r.name, err, tt.err)
+				}
+			}
+		})
+	}
+}
+
+// Value, that fails to be written
+type writeError struct {
+	name string
+	in   encoder
+	err  string // Expected error message
+}
+
+func testWriteErrors(t *testing.T, tests []writeError) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.in.MarshalBinary(); err == nil || err.Error() != tt.err {
+				t.Fatalf("MarshalBinary() error = %v, want %s", err, tt.err)
+			}
+			if _, err := tt.in.WriteTo(io.Discard); err == nil || err.Error() != tt.err {
+				t.Fatalf("WriteTo() error = %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
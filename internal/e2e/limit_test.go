@@ -0,0 +1,99 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/amanofbits/simser/pkg/simser"
+)
+
+func TestLimits(t *testing.T) {
+	testRoundTrips(t, []roundTrip{
+		{
+			name: "within limits",
+			in:   &Limited{Items: []uint32{1, 2, 3, 4}, S: "abcde", Big: []uint16{7}},
+			data: []byte{
+				0x04, 0x00, 1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0, 4, 0, 0, 0, // N, Items
+				0x05, 0x00, 'a', 'b', 'c', 'd', 'e', // SLen, S
+				0x01, 0x00, 0x00, 0x00, 0x07, 0x00, // M, Big
+			},
+			out:  &Limited{},
+			want: &Limited{N: 4, Items: []uint32{1, 2, 3, 4}, SLen: 5, S: "abcde", M: 1, Big: []uint16{7}},
+		},
+	})
+
+	tests := []struct {
+		name  string
+		data  []byte
+		field string
+		want  simser.LengthError
+	}{
+		{
+			name:  "negative",
+			data:  []byte{0xFF, 0xFF},
+			field: "Items",
+			want:  simser.LengthError{Length: -1, Max: 4},
+		},
+		{
+			name:  "slice over max",
+			data:  []byte{0x05, 0x00},
+			field: "Items",
+			want:  simser.LengthError{Length: 5, Max: 4},
+		},
+		{
+			name:  "string over max",
+			data:  []byte{0x00, 0x00, 0x06, 0x00},
+			field: "S",
+			want:  simser.LengthError{Length: 6, Max: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, r := range readers {
+				err := r.read(&Limited{}, tt.data)
+				var fErr *simser.FieldError
+				var lErr *simser.LengthError
+				if !errors.As(err, &fErr) || fErr.Field != tt.field || !errors.As(err, &lErr) || *lErr != tt.want {
+					t.Fatalf("%s() error = %v, want %s: %v", r.name, err, tt.field, &tt.want)
+				}
+			}
+		})
+	}
+}
+
+// Hostile length without limit doesn't cause allocation of its size, when reading from io.Reader
+func TestUnlimitedLength(t *testing.T) {
+	data := []byte{0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0x3F, 1, 2, 3}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := (&Limited{}).ReadFrom(bytes.NewReader(data))
+	runtime.ReadMemStats(&after)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("ReadFrom() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Fatalf("ReadFrom() allocated %d bytes, for length 0x3FFFFFFF", allocated)
+	}
+
+	if _, err := (&Limited{}).DecodeFrom(data); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("DecodeFrom() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import "testing"
+
+func TestNested(t *testing.T) {
+	shape := Shape{
+		Origin: Pair{1, 2},
+		Corner: Pair{3, 4},
+		Arr:    [2]Pair{{5, 6}, {7, 8}},
+		N:      1,
+		Pts:    []Pair{{9, 10}},
+	}
+	shape.Inline.X, shape.Inline.Y = -1, 0x0102
+
+	testRoundTrips(t, []roundTrip{
+		{
+			name: "struct",
+			in:   &Pair{0x0102, 0x0304},
+			data: []byte{0x02, 0x01, 0x04, 0x03},
+			out:  &Pair{},
+		},
+		{
+			name: "nested",
+			in:   &shape,
+			data: []byte{
+				0x01, 0x00, 0x02, 0x00, // Origin
+				0x00, 0x03, 0x04, 0x00, // Corner, big-endian, except its little-endian B
+				0xFF, 0x01, 0x02, // Inline
+				0x05, 0x00, 0x06, 0x00, 0x07, 0x00, 0x08, 0x00, // Arr
+				0x01,                   // N
+				0x09, 0x00, 0x0A, 0x00, // Pts
+			},
+			out: &Shape{},
+		},
+		{
+			name: "empty slice",
+			in:   &Shape{Pts: []Pair{}},
+			data: make([]byte, 20),
+			out:  &Shape{},
+		},
+	})
+
+	testReadErrors(t, []readError{
+		{
+			name: "truncated slice",
+			data: append(make([]byte, 19), 2, 1, 0, 0, 0, 2, 0),
+			out:  &Shape{},
+			err:  "Shape.Pts at offset 20: unexpected EOF",
+		},
+	})
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import "testing"
+
+func TestPadding(t *testing.T) {
+	testRoundTrips(t, []roundTrip{
+		{
+			name: "padding",
+			in:   &Padded{A: 1, B: 2, W: 3},
+			data: []byte{0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0xEE},
+			out:  &Padded{},
+			want: &Padded{A: 1, B: 2, W: 0xEE},
+		},
+		{
+			name: "only padding is read",
+			in:   &Skipped{X: 5},
+			data: []byte{0x00, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00},
+			out:  &Skipped{},
+			want: &Skipped{},
+		},
+	})
+
+	// Padding bytes are not checked on read
+	for _, r := range readers {
+		out := &Padded{}
+		if err := r.read(out, []byte{0x01, 0x11, 0x22, 0x33, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03}); err != nil {
+			t.Fatalf("%s(): %v", r.name, err)
+		}
+		if want := (Padded{A: 1, B: 2, W: 3}); *out != want {
+			t.Fatalf("%s() = %+v, want %+v", r.name, *out, want)
+		}
+	}
+}
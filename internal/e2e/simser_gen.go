@@ -0,0 +1,4177 @@
+// Code generated by "simser -types=all -bytes -interfaces=std"; DO NOT EDIT.
+
+package e2e
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"github.com/amanofbits/simser/pkg/simser"
+	"io"
+	"math"
+	"strings"
+)
+
+// EncodedSize returns the size of serialized o, in bytes.
+func (o *Counted) EncodedSize() int {
+	return 2 + (4 * len(o.Items))
+}
+
+func (o *Counted) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	sLen, sElSize := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Counted", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// Count
+	errField, errOffset = "Count", n
+	p, toRead = 0, 2
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Count = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	// Items
+	errField, errOffset = "Items", n
+	sLen, sElSize = int(o.Count), 4
+	if sLen < 0 || sLen > math.MaxInt/4 {
+		return n, &simser.LengthError{Length: sLen, Max: -1}
+	}
+	p, toRead = 0, sLen*sElSize
+	b, nRead, err = simser.ReadFull(r, b, toRead)
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Items = make([]uint32, sLen)
+	for i := 0; i < len(o.Items); i++ {
+		o.Items[i] = uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24
+		p += 4
+	}
+	if len(o.Items) != int(o.Count) {
+		return n, fmt.Errorf("length %d of Items does not match Count %d", len(o.Items), o.Count)
+	}
+
+	return n, err
+}
+
+func (o *Counted) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	sLen, sElSize := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Counted", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// Count
+	errField, errOffset = "Count", n
+	p, toRead = 0, 2
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Count = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	// Items
+	errField, errOffset = "Items", n
+	sLen, sElSize = int(o.Count), 4
+	if sLen < 0 || sLen > math.MaxInt/4 {
+		return n, &simser.LengthError{Length: sLen, Max: -1}
+	}
+	p, toRead = 0, sLen*sElSize
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Items = make([]uint32, sLen)
+	for i := 0; i < len(o.Items); i++ {
+		o.Items[i] = uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24
+		p += 4
+	}
+	if len(o.Items) != int(o.Count) {
+		return n, fmt.Errorf("length %d of Items does not match Count %d", len(o.Items), o.Count)
+	}
+
+	return n, err
+}
+
+func (o *Counted) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Counted) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Counted: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Counted)(nil)
+	_ encoding.BinaryUnmarshaler = (*Counted)(nil)
+)
+
+func (o *Counted) MarshalTo(dst []byte) (n int, err error) {
+	b := dst[:0:len(dst)]
+
+	// Count
+	if uint64(len(o.Items)) > 0xFFFF {
+		return 0, fmt.Errorf("Items: length %d overflows uint16", len(o.Items))
+	}
+	b = append(b, byte(uint16(len(o.Items))), byte(uint16(len(o.Items))>>8))
+
+	// Items
+	for i := 0; i < len(o.Items); i++ {
+		b = append(b, byte(o.Items[i]), byte(o.Items[i]>>8), byte(o.Items[i]>>16), byte(o.Items[i]>>24))
+	}
+	if len(b) > len(dst) {
+		return 0, io.ErrShortBuffer
+	}
+	return len(b), nil
+}
+
+func (o *Counted) AppendBinary(dst []byte) ([]byte, error) {
+	size := o.EncodedSize()
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Counted) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, o.EncodedSize())
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Counted) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Counted) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Counted)(nil)
+	_ encoding.BinaryMarshaler = (*Counted)(nil)
+)
+
+// FixedEncodedSize is the size of serialized Fixed, in bytes.
+const FixedEncodedSize = 33
+
+func (o *Fixed) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Fixed", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// Magic
+	errField, errOffset = "Magic", n
+	p, toRead = 0, 33
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Magic = uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24
+	p += 4
+	if o.Magic != uint32(0x44584946) {
+		return n, &simser.ConstMismatchError{Type: "Fixed", Field: "Magic", Offset: n - toRead + p - 4, Expected: uint32(0x44584946), Actual: o.Magic}
+	}
+
+	// Version|Flags
+	errField, errOffset = "Version|Flags", n-toRead+p
+	{
+		c := uint8(b[p])
+		p += 1
+		o.Version = c >> 4
+		o.Flags = c & 0xF
+	}
+
+	// _
+	errField, errOffset = "_", n-toRead+p
+	p += 3
+
+	// Skipped
+	errField, errOffset = "Skipped", n-toRead+p
+	p += 4
+
+	// Pos
+	errField, errOffset = "Pos", n-toRead+p
+	for i := 0; i < len(o.Pos); i++ {
+		o.Pos[i] = math.Float32frombits(uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24)
+		p += 4
+	}
+
+	// ID
+	errField, errOffset = "ID", n-toRead+p
+	o.ID = int64(b[p])<<56 | int64(b[p+1])<<48 | int64(b[p+2])<<40 | int64(b[p+3])<<32 | int64(b[p+4])<<24 |
+		int64(b[p+5])<<16 | int64(b[p+6])<<8 | int64(b[p+7])
+	p += 8
+
+	// Ok
+	errField, errOffset = "Ok", n-toRead+p
+	o.Ok = b[p] != 0
+	p += 1
+
+	return n, err
+}
+
+func (o *Fixed) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Fixed", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// Magic
+	errField, errOffset = "Magic", n
+	p, toRead = 0, 33
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Magic = uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24
+	p += 4
+	if o.Magic != uint32(0x44584946) {
+		return n, &simser.ConstMismatchError{Type: "Fixed", Field: "Magic", Offset: n - toRead + p - 4, Expected: uint32(0x44584946), Actual: o.Magic}
+	}
+
+	// Version|Flags
+	errField, errOffset = "Version|Flags", n-toRead+p
+	{
+		c := uint8(b[p])
+		p += 1
+		o.Version = c >> 4
+		o.Flags = c & 0xF
+	}
+
+	// _
+	errField, errOffset = "_", n-toRead+p
+	p += 3
+
+	// Skipped
+	errField, errOffset = "Skipped", n-toRead+p
+	p += 4
+
+	// Pos
+	errField, errOffset = "Pos", n-toRead+p
+	for i := 0; i < len(o.Pos); i++ {
+		o.Pos[i] = math.Float32frombits(uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24)
+		p += 4
+	}
+
+	// ID
+	errField, errOffset = "ID", n-toRead+p
+	o.ID = int64(b[p])<<56 | int64(b[p+1])<<48 | int64(b[p+2])<<40 | int64(b[p+3])<<32 | int64(b[p+4])<<24 |
+		int64(b[p+5])<<16 | int64(b[p+6])<<8 | int64(b[p+7])
+	p += 8
+
+	// Ok
+	errField, errOffset = "Ok", n-toRead+p
+	o.Ok = b[p] != 0
+	p += 1
+
+	return n, err
+}
+
+func (o *Fixed) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Fixed) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Fixed: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Fixed)(nil)
+	_ encoding.BinaryUnmarshaler = (*Fixed)(nil)
+)
+
+func (o *Fixed) MarshalTo(dst []byte) (n int, err error) {
+	if len(dst) < FixedEncodedSize {
+		return 0, io.ErrShortBuffer
+	}
+	b := dst[:0]
+
+	// Magic
+	b = append(b, 0x46, 0x49, 0x58, 0x44)
+
+	// Version|Flags
+	{
+		if o.Version > 0xF {
+			return 0, fmt.Errorf("Version: value %d overflows 4 bits", o.Version)
+		}
+		if o.Flags > 0xF {
+			return 0, fmt.Errorf("Flags: value %d overflows 4 bits", o.Flags)
+		}
+		var c uint8
+		c |= uint8(o.Version) << 4
+		c |= uint8(o.Flags)
+		b = append(b, byte(c))
+	}
+
+	// _
+	b = append(b, make([]byte, 3)...)
+
+	// Skipped
+	b = append(b, byte(o.Skipped), byte(o.Skipped>>8), byte(o.Skipped>>16), byte(o.Skipped>>24))
+
+	// Pos
+	for i := 0; i < len(o.Pos); i++ {
+		b = append(b, byte(math.Float32bits(o.Pos[i])), byte(math.Float32bits(o.Pos[i])>>8), byte(math.Float32bits(o.Pos[i])>>16), byte(math.Float32bits(o.Pos[i])>>24))
+	}
+
+	// ID
+	b = append(b, byte(o.ID>>56), byte(o.ID>>48), byte(o.ID>>40), byte(o.ID>>32), byte(o.ID>>24), byte(o.ID>>16), byte(o.ID>>8), byte(o.ID))
+
+	// Ok
+	if o.Ok {
+		b = append(b, 1)
+	} else {
+		b = append(b, 0)
+	}
+	return len(b), nil
+}
+
+func (o *Fixed) AppendBinary(dst []byte) ([]byte, error) {
+	size := FixedEncodedSize
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Fixed) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, FixedEncodedSize)
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Fixed) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Fixed) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Fixed)(nil)
+	_ encoding.BinaryMarshaler = (*Fixed)(nil)
+)
+
+// SkippedEncodedSize is the size of serialized Skipped, in bytes.
+const SkippedEncodedSize = 8
+
+func (o *Skipped) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Skipped", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// _
+	errField, errOffset = "_", n
+	p, toRead = 0, 8
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	p += 4
+
+	// X
+	errField, errOffset = "X", n-toRead+p
+	p += 4
+
+	return n, err
+}
+
+func (o *Skipped) DecodeFrom(src []byte) (n int, err error) {
+	p, toRead := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Skipped", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// _
+	errField, errOffset = "_", n
+	p, toRead = 0, 8
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	n += toRead
+	p += 4
+
+	// X
+	errField, errOffset = "X", n-toRead+p
+	p += 4
+
+	return n, err
+}
+
+func (o *Skipped) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Skipped) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Skipped: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Skipped)(nil)
+	_ encoding.BinaryUnmarshaler = (*Skipped)(nil)
+)
+
+func (o *Skipped) MarshalTo(dst []byte) (n int, err error) {
+	if len(dst) < SkippedEncodedSize {
+		return 0, io.ErrShortBuffer
+	}
+	b := dst[:0]
+
+	// _
+	b = append(b, make([]byte, 4)...)
+
+	// X
+	b = append(b, byte(o.X), byte(o.X>>8), byte(o.X>>16), byte(o.X>>24))
+	return len(b), nil
+}
+
+func (o *Skipped) AppendBinary(dst []byte) ([]byte, error) {
+	size := SkippedEncodedSize
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Skipped) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, SkippedEncodedSize)
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Skipped) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Skipped) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Skipped)(nil)
+	_ encoding.BinaryMarshaler = (*Skipped)(nil)
+)
+
+// EncodedSize returns the size of serialized o, in bytes.
+func (o *Message) EncodedSize() int {
+	size := 1 + (simser.UvarintSize(uint64(o.Seq))) + (simser.VarintSize(int64(o.Delta))) + (len(o.Topic) + 1) + (2 + len(o.Body))
+	// Score
+	if o.Flags&1 != 0 {
+		size += 8
+	}
+	return size
+}
+
+func (o *Message) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Message", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// Flags
+	errField, errOffset = "Flags", n
+	p, toRead = 0, 1
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Flags = uint8(b[p])
+	p += 1
+
+	// Seq
+	errField, errOffset = "Seq", n
+	{
+		var v uint64
+		b, toRead, err = simser.ReadVarint(r, b)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		if v, _, err = simser.Uvarint(b[:toRead], 64); err != nil {
+			return n, err
+		}
+		o.Seq = uint64(v)
+	}
+
+	// Delta
+	errField, errOffset = "Delta", n
+	{
+		var v int64
+		b, toRead, err = simser.ReadVarint(r, b)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		if v, _, err = simser.Varint(b[:toRead], 32); err != nil {
+			return n, err
+		}
+		o.Delta = int32(v)
+	}
+
+	// Topic
+	errField, errOffset = "Topic", n
+	toRead = 0
+	for {
+		if toRead > 64 {
+			return n, fmt.Errorf("string is not NUL-terminated within 65 bytes")
+		}
+		if toRead == len(b) {
+			b = append(b, 0)
+		}
+		nRead, err = io.ReadFull(r, b[toRead:toRead+1])
+		n += nRead
+		if err != nil {
+			return n, err
+		}
+		if b[toRead] == 0 {
+			break
+		}
+		toRead++
+	}
+	o.Topic = string(b[:toRead])
+
+	// Body
+	errField, errOffset = "Body", n
+	p, toRead = 0, 2
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	{
+		strLen := uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+		p, toRead = 0, int(strLen)
+	}
+	if toRead < 0 {
+		return n, &simser.LengthError{Length: toRead, Max: -1}
+	}
+	b, nRead, err = simser.ReadFull(r, b, toRead)
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Body = string(b[:toRead])
+	p += toRead
+
+	// Score
+	if o.Flags&1 != 0 {
+		errField, errOffset = "Score", n
+		p, toRead = 0, 8
+		if toRead > cap(b) {
+			b = make([]byte, toRead)
+		}
+		nRead, err = io.ReadFull(r, b[:toRead])
+		n += nRead
+		if err != nil {
+			return n, err
+		}
+		o.Score = math.Float64frombits(uint64(b[p]) | uint64(b[p+1])<<8 | uint64(b[p+2])<<16 | uint64(b[p+3])<<24 | uint64(b[p+4])<<32 |
+			uint64(b[p+5])<<40 | uint64(b[p+6])<<48 | uint64(b[p+7])<<56)
+		p += 8
+	} else {
+		o.Score = *new(float64)
+	}
+
+	return n, err
+}
+
+func (o *Message) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Message", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// Flags
+	errField, errOffset = "Flags", n
+	p, toRead = 0, 1
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Flags = uint8(b[p])
+	p += 1
+
+	// Seq
+	errField, errOffset = "Seq", n
+	{
+		var v uint64
+		v, toRead, err = simser.Uvarint(src[n:], 64)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		o.Seq = uint64(v)
+	}
+
+	// Delta
+	errField, errOffset = "Delta", n
+	{
+		var v int64
+		v, toRead, err = simser.Varint(src[n:], 32)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		o.Delta = int32(v)
+	}
+
+	// Topic
+	errField, errOffset = "Topic", n
+	b = src[n:]
+	if len(b) > 65 {
+		b = b[:65]
+	}
+	toRead = bytes.IndexByte(b, 0)
+	if toRead < 0 {
+		if len(b) > 64 {
+			return n, fmt.Errorf("string is not NUL-terminated within 65 bytes")
+		}
+		return n, io.ErrUnexpectedEOF
+	}
+	o.Topic = string(b[:toRead])
+	n += toRead + 1
+
+	// Body
+	errField, errOffset = "Body", n
+	p, toRead = 0, 2
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	{
+		strLen := uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+		p, toRead = 0, int(strLen)
+	}
+	if toRead < 0 {
+		return n, &simser.LengthError{Length: toRead, Max: -1}
+	}
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Body = string(b[:toRead])
+	p += toRead
+
+	// Score
+	if o.Flags&1 != 0 {
+		errField, errOffset = "Score", n
+		p, toRead = 0, 8
+		if len(src)-n < toRead {
+			return n, io.ErrUnexpectedEOF
+		}
+		b = src[n : n+toRead]
+		n += toRead
+		o.Score = math.Float64frombits(uint64(b[p]) | uint64(b[p+1])<<8 | uint64(b[p+2])<<16 | uint64(b[p+3])<<24 | uint64(b[p+4])<<32 |
+			uint64(b[p+5])<<40 | uint64(b[p+6])<<48 | uint64(b[p+7])<<56)
+		p += 8
+	} else {
+		o.Score = *new(float64)
+	}
+
+	return n, err
+}
+
+func (o *Message) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Message) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Message: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Message)(nil)
+	_ encoding.BinaryUnmarshaler = (*Message)(nil)
+)
+
+func (o *Message) MarshalTo(dst []byte) (n int, err error) {
+	b := dst[:0:len(dst)]
+
+	// Flags
+	b = append(b, byte(o.Flags))
+
+	// Seq
+	b = simser.AppendUvarint(b, uint64(o.Seq))
+
+	// Delta
+	b = simser.AppendVarint(b, int64(o.Delta))
+
+	// Topic
+	if len(o.Topic) > 64 {
+		return 0, fmt.Errorf("Topic: string length %d exceeds 64", len(o.Topic))
+	}
+	if strings.IndexByte(o.Topic, 0) >= 0 {
+		return 0, fmt.Errorf("Topic: string contains NUL byte")
+	}
+	b = append(b, o.Topic...)
+	b = append(b, 0)
+
+	// Body
+	if uint64(len(o.Body)) > 0xFFFF {
+		return 0, fmt.Errorf("Body: string length %d overflows uint16 length prefix", len(o.Body))
+	}
+	b = append(b, byte(uint16(len(o.Body))), byte(uint16(len(o.Body))>>8))
+	b = append(b, o.Body...)
+
+	// Score
+	if o.Flags&1 != 0 {
+		b = append(b, byte(math.Float64bits(o.Score)), byte(math.Float64bits(o.Score)>>8), byte(math.Float64bits(o.Score)>>16), byte(math.Float64bits(o.Score)>>24), byte(math.Float64bits(o.Score)>>32), byte(math.Float64bits(o.Score)>>40), byte(math.Float64bits(o.Score)>>48), byte(math.Float64bits(o.Score)>>56))
+	}
+	if len(b) > len(dst) {
+		return 0, io.ErrShortBuffer
+	}
+	return len(b), nil
+}
+
+func (o *Message) AppendBinary(dst []byte) ([]byte, error) {
+	size := o.EncodedSize()
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Message) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, o.EncodedSize())
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Message) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Message) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Message)(nil)
+	_ encoding.BinaryMarshaler = (*Message)(nil)
+)
+
+// EncodedSize returns the size of serialized o, in bytes.
+func (o *Coded) EncodedSize() int {
+	return 3 + (len(wordsWrite(nil, o.Words)))
+}
+
+func (o *Coded) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Coded", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// Kind
+	errField, errOffset = "Kind", n
+	p, toRead = 0, 1
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Kind = uint8(b[p])
+	p += 1
+
+	// Words
+	errField, errOffset = "Words", n
+	{
+		k := 0
+		toRead = 0
+		for {
+			o.Words, k, err = wordsRead(b[:toRead])
+			if err == nil {
+				break
+			}
+			if err != io.ErrUnexpectedEOF {
+				return n, err
+			}
+			if k <= toRead {
+				return n, fmt.Errorf("wordsRead returned io.ErrUnexpectedEOF, asking for %d bytes, not more than %d it got", k, toRead)
+			}
+			if k > 1024 {
+				return n, fmt.Errorf("encoded value exceeds 1024 bytes")
+			}
+			if k > cap(b) {
+				b = append(b[:toRead], make([]byte, k-toRead)...)
+			}
+			nRead, err = io.ReadFull(r, b[toRead:k])
+			n += nRead
+			if err != nil {
+				return n, err
+			}
+			toRead = k
+		}
+		if k != toRead {
+			return n, fmt.Errorf("wordsRead reported %d bytes read, out of %d", k, toRead)
+		}
+	}
+
+	// Tail
+	errField, errOffset = "Tail", n
+	p, toRead = 0, 2
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Tail = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	return n, err
+}
+
+func (o *Coded) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Coded", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// Kind
+	errField, errOffset = "Kind", n
+	p, toRead = 0, 1
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Kind = uint8(b[p])
+	p += 1
+
+	// Words
+	errField, errOffset = "Words", n
+	o.Words, toRead, err = wordsRead(src[n:])
+	if err != nil {
+		return n, err
+	}
+	if toRead < 0 || toRead > len(src)-n {
+		return n, fmt.Errorf("wordsRead reported %d bytes read, out of %d", toRead, len(src)-n)
+	}
+	n += toRead
+
+	// Tail
+	errField, errOffset = "Tail", n
+	p, toRead = 0, 2
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Tail = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	return n, err
+}
+
+func (o *Coded) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Coded) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Coded: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Coded)(nil)
+	_ encoding.BinaryUnmarshaler = (*Coded)(nil)
+)
+
+func (o *Coded) MarshalTo(dst []byte) (n int, err error) {
+	b := dst[:0:len(dst)]
+
+	// Kind
+	b = append(b, byte(o.Kind))
+
+	// Words
+	b = wordsWrite(b, o.Words)
+
+	// Tail
+	b = append(b, byte(o.Tail), byte(o.Tail>>8))
+	if len(b) > len(dst) {
+		return 0, io.ErrShortBuffer
+	}
+	return len(b), nil
+}
+
+func (o *Coded) AppendBinary(dst []byte) ([]byte, error) {
+	size := o.EncodedSize()
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Coded) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, o.EncodedSize())
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Coded) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Coded) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Coded)(nil)
+	_ encoding.BinaryMarshaler = (*Coded)(nil)
+)
+
+// PairEncodedSize is the size of serialized Pair, in bytes.
+const PairEncodedSize = 4
+
+func (o *Pair) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Pair", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// A
+	errField, errOffset = "A", n
+	p, toRead = 0, 4
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.A = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	// B
+	errField, errOffset = "B", n-toRead+p
+	o.B = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	return n, err
+}
+
+func (o *Pair) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Pair", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// A
+	errField, errOffset = "A", n
+	p, toRead = 0, 4
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.A = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	// B
+	errField, errOffset = "B", n-toRead+p
+	o.B = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	return n, err
+}
+
+func (o *Pair) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Pair) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Pair: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Pair)(nil)
+	_ encoding.BinaryUnmarshaler = (*Pair)(nil)
+)
+
+func (o *Pair) MarshalTo(dst []byte) (n int, err error) {
+	if len(dst) < PairEncodedSize {
+		return 0, io.ErrShortBuffer
+	}
+	b := dst[:0]
+
+	// A
+	b = append(b, byte(o.A), byte(o.A>>8))
+
+	// B
+	b = append(b, byte(o.B), byte(o.B>>8))
+	return len(b), nil
+}
+
+func (o *Pair) AppendBinary(dst []byte) ([]byte, error) {
+	size := PairEncodedSize
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Pair) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, PairEncodedSize)
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Pair) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Pair) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Pair)(nil)
+	_ encoding.BinaryMarshaler = (*Pair)(nil)
+)
+
+// EncodedSize returns the size of serialized o, in bytes.
+func (o *Shape) EncodedSize() int {
+	return 20 + (4 * len(o.Pts))
+}
+
+func (o *Shape) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	sLen, sElSize := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Shape", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// Origin
+	errField, errOffset = "Origin", n
+	p, toRead = 0, 20
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Origin.A = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+	o.Origin.B = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	// Corner
+	errField, errOffset = "Corner", n-toRead+p
+	o.Corner.A = uint16(b[p])<<8 | uint16(b[p+1])
+	p += 2
+	o.Corner.B = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	// Inline
+	errField, errOffset = "Inline", n-toRead+p
+	o.Inline.X = int8(b[p])
+	p += 1
+	o.Inline.Y = int16(b[p])<<8 | int16(b[p+1])
+	p += 2
+
+	// Arr
+	errField, errOffset = "Arr", n-toRead+p
+	for i := 0; i < len(o.Arr); i++ {
+		o.Arr[i].A = uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+		o.Arr[i].B = uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+	}
+
+	// N
+	errField, errOffset = "N", n-toRead+p
+	o.N = uint8(b[p])
+	p += 1
+
+	// Pts
+	errField, errOffset = "Pts", n
+	sLen, sElSize = int(o.N), 4
+	if sLen < 0 || sLen > math.MaxInt/4 {
+		return n, &simser.LengthError{Length: sLen, Max: -1}
+	}
+	p, toRead = 0, sLen*sElSize
+	b, nRead, err = simser.ReadFull(r, b, toRead)
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Pts = make([]Pair, sLen)
+	for i := 0; i < len(o.Pts); i++ {
+		o.Pts[i].A = uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+		o.Pts[i].B = uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+	}
+	if len(o.Pts) != int(o.N) {
+		return n, fmt.Errorf("length %d of Pts does not match N %d", len(o.Pts), o.N)
+	}
+
+	return n, err
+}
+
+func (o *Shape) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	sLen, sElSize := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Shape", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// Origin
+	errField, errOffset = "Origin", n
+	p, toRead = 0, 20
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Origin.A = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+	o.Origin.B = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	// Corner
+	errField, errOffset = "Corner", n-toRead+p
+	o.Corner.A = uint16(b[p])<<8 | uint16(b[p+1])
+	p += 2
+	o.Corner.B = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	// Inline
+	errField, errOffset = "Inline", n-toRead+p
+	o.Inline.X = int8(b[p])
+	p += 1
+	o.Inline.Y = int16(b[p])<<8 | int16(b[p+1])
+	p += 2
+
+	// Arr
+	errField, errOffset = "Arr", n-toRead+p
+	for i := 0; i < len(o.Arr); i++ {
+		o.Arr[i].A = uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+		o.Arr[i].B = uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+	}
+
+	// N
+	errField, errOffset = "N", n-toRead+p
+	o.N = uint8(b[p])
+	p += 1
+
+	// Pts
+	errField, errOffset = "Pts", n
+	sLen, sElSize = int(o.N), 4
+	if sLen < 0 || sLen > math.MaxInt/4 {
+		return n, &simser.LengthError{Length: sLen, Max: -1}
+	}
+	p, toRead = 0, sLen*sElSize
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Pts = make([]Pair, sLen)
+	for i := 0; i < len(o.Pts); i++ {
+		o.Pts[i].A = uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+		o.Pts[i].B = uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+	}
+	if len(o.Pts) != int(o.N) {
+		return n, fmt.Errorf("length %d of Pts does not match N %d", len(o.Pts), o.N)
+	}
+
+	return n, err
+}
+
+func (o *Shape) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Shape) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Shape: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Shape)(nil)
+	_ encoding.BinaryUnmarshaler = (*Shape)(nil)
+)
+
+func (o *Shape) MarshalTo(dst []byte) (n int, err error) {
+	b := dst[:0:len(dst)]
+
+	// Origin
+	b = append(b, byte(o.Origin.A), byte(o.Origin.A>>8))
+	b = append(b, byte(o.Origin.B), byte(o.Origin.B>>8))
+
+	// Corner
+	b = append(b, byte(o.Corner.A>>8), byte(o.Corner.A))
+	b = append(b, byte(o.Corner.B), byte(o.Corner.B>>8))
+
+	// Inline
+	b = append(b, byte(o.Inline.X))
+	b = append(b, byte(o.Inline.Y>>8), byte(o.Inline.Y))
+
+	// Arr
+	for i := 0; i < len(o.Arr); i++ {
+		b = append(b, byte(o.Arr[i].A), byte(o.Arr[i].A>>8))
+		b = append(b, byte(o.Arr[i].B), byte(o.Arr[i].B>>8))
+	}
+
+	// N
+	if uint64(len(o.Pts)) > 0xFF {
+		return 0, fmt.Errorf("Pts: length %d overflows uint8", len(o.Pts))
+	}
+	b = append(b, byte(uint8(len(o.Pts))))
+
+	// Pts
+	for i := 0; i < len(o.Pts); i++ {
+		b = append(b, byte(o.Pts[i].A), byte(o.Pts[i].A>>8))
+		b = append(b, byte(o.Pts[i].B), byte(o.Pts[i].B>>8))
+	}
+	if len(b) > len(dst) {
+		return 0, io.ErrShortBuffer
+	}
+	return len(b), nil
+}
+
+func (o *Shape) AppendBinary(dst []byte) ([]byte, error) {
+	size := o.EncodedSize()
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Shape) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, o.EncodedSize())
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Shape) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Shape) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Shape)(nil)
+	_ encoding.BinaryMarshaler = (*Shape)(nil)
+)
+
+// SkipsEncodedSize is the size of serialized Skips, in bytes.
+const SkipsEncodedSize = 5
+
+func (o *Skips) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Skips", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// A
+	errField, errOffset = "A", n
+	p, toRead = 0, 5
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.A = uint8(b[p])
+	p += 1
+
+	// R
+	errField, errOffset = "R", n-toRead+p
+	p += 2
+
+	// W
+	errField, errOffset = "W", n-toRead+p
+	o.W = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	return n, err
+}
+
+func (o *Skips) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Skips", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// A
+	errField, errOffset = "A", n
+	p, toRead = 0, 5
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.A = uint8(b[p])
+	p += 1
+
+	// R
+	errField, errOffset = "R", n-toRead+p
+	p += 2
+
+	// W
+	errField, errOffset = "W", n-toRead+p
+	o.W = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	return n, err
+}
+
+func (o *Skips) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Skips) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Skips: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Skips)(nil)
+	_ encoding.BinaryUnmarshaler = (*Skips)(nil)
+)
+
+func (o *Skips) MarshalTo(dst []byte) (n int, err error) {
+	if len(dst) < SkipsEncodedSize {
+		return 0, io.ErrShortBuffer
+	}
+	b := dst[:0]
+
+	// A
+	b = append(b, byte(o.A))
+
+	// R
+	b = append(b, byte(o.R), byte(o.R>>8))
+
+	// W
+	b = append(b, make([]byte, 2)...)
+	return len(b), nil
+}
+
+func (o *Skips) AppendBinary(dst []byte) ([]byte, error) {
+	size := SkipsEncodedSize
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Skips) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, SkipsEncodedSize)
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Skips) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Skips) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Skips)(nil)
+	_ encoding.BinaryMarshaler = (*Skips)(nil)
+)
+
+// PaddedEncodedSize is the size of serialized Padded, in bytes.
+const PaddedEncodedSize = 11
+
+func (o *Padded) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Padded", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// A
+	errField, errOffset = "A", n
+	p, toRead = 0, 11
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.A = uint8(b[p])
+	p += 1
+
+	// _
+	errField, errOffset = "_", n-toRead+p
+	p += 3
+
+	// B
+	errField, errOffset = "B", n-toRead+p
+	o.B = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	// _
+	errField, errOffset = "_", n-toRead+p
+	p += 4
+
+	// W
+	errField, errOffset = "W", n-toRead+p
+	o.W = uint8(b[p])
+	p += 1
+
+	return n, err
+}
+
+func (o *Padded) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Padded", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// A
+	errField, errOffset = "A", n
+	p, toRead = 0, 11
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.A = uint8(b[p])
+	p += 1
+
+	// _
+	errField, errOffset = "_", n-toRead+p
+	p += 3
+
+	// B
+	errField, errOffset = "B", n-toRead+p
+	o.B = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	// _
+	errField, errOffset = "_", n-toRead+p
+	p += 4
+
+	// W
+	errField, errOffset = "W", n-toRead+p
+	o.W = uint8(b[p])
+	p += 1
+
+	return n, err
+}
+
+func (o *Padded) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Padded) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Padded: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Padded)(nil)
+	_ encoding.BinaryUnmarshaler = (*Padded)(nil)
+)
+
+func (o *Padded) MarshalTo(dst []byte) (n int, err error) {
+	if len(dst) < PaddedEncodedSize {
+		return 0, io.ErrShortBuffer
+	}
+	b := dst[:0]
+
+	// A
+	b = append(b, byte(o.A))
+
+	// _
+	b = append(b, make([]byte, 3)...)
+
+	// B
+	b = append(b, byte(o.B), byte(o.B>>8))
+
+	// _
+	for i := 0; i < 4; i++ {
+		b = append(b, 0xFF)
+	}
+
+	// W
+	for i := 0; i < 1; i++ {
+		b = append(b, 0xEE)
+	}
+	return len(b), nil
+}
+
+func (o *Padded) AppendBinary(dst []byte) ([]byte, error) {
+	size := PaddedEncodedSize
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Padded) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, PaddedEncodedSize)
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Padded) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Padded) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Padded)(nil)
+	_ encoding.BinaryMarshaler = (*Padded)(nil)
+)
+
+// EncodedSize returns the size of serialized o, in bytes.
+func (o *Strs) EncodedSize() int {
+	return 14 + (len(o.Name)) + (2 + len(o.Pre)) + (len(o.C) + 1)
+}
+
+func (o *Strs) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Strs", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// NameLen
+	errField, errOffset = "NameLen", n
+	p, toRead = 0, 1
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.NameLen = uint8(b[p])
+	p += 1
+
+	// Name
+	errField, errOffset = "Name", n
+	p, toRead = 0, int(o.NameLen)
+	if toRead < 0 {
+		return n, &simser.LengthError{Length: toRead, Max: -1}
+	}
+	b, nRead, err = simser.ReadFull(r, b, toRead)
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Name = string(b[p : p+toRead])
+	p += toRead
+	if len(o.Name) != int(o.NameLen) {
+		return n, fmt.Errorf("length %d of Name does not match NameLen %d", len(o.Name), o.NameLen)
+	}
+
+	// Pre
+	errField, errOffset = "Pre", n
+	p, toRead = 0, 2
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	{
+		strLen := uint16(b[p])<<8 | uint16(b[p+1])
+		p += 2
+		p, toRead = 0, int(strLen)
+	}
+	if toRead < 0 {
+		return n, &simser.LengthError{Length: toRead, Max: -1}
+	}
+	b, nRead, err = simser.ReadFull(r, b, toRead)
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Pre = Label(b[:toRead])
+	p += toRead
+
+	// Fixed
+	errField, errOffset = "Fixed", n
+	p, toRead = 0, 6
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Fixed = string(bytes.TrimRight(b[p:p+6], "\x00"))
+	p += 6
+
+	// C
+	errField, errOffset = "C", n
+	toRead = 0
+	for {
+		if toRead > 8 {
+			return n, fmt.Errorf("string is not NUL-terminated within 9 bytes")
+		}
+		if toRead == len(b) {
+			b = append(b, 0)
+		}
+		nRead, err = io.ReadFull(r, b[toRead:toRead+1])
+		n += nRead
+		if err != nil {
+			return n, err
+		}
+		if b[toRead] == 0 {
+			break
+		}
+		toRead++
+	}
+	o.C = string(b[:toRead])
+
+	// Labels
+	errField, errOffset = "Labels", n
+	p, toRead = 0, 7
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	for i := 0; i < len(o.Labels); i++ {
+		o.Labels[i] = Label(bytes.TrimRight(b[p:p+3], "\x00"))
+		p += 3
+	}
+
+	// Tail
+	errField, errOffset = "Tail", n-toRead+p
+	o.Tail = uint8(b[p])
+	p += 1
+
+	return n, err
+}
+
+func (o *Strs) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Strs", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// NameLen
+	errField, errOffset = "NameLen", n
+	p, toRead = 0, 1
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.NameLen = uint8(b[p])
+	p += 1
+
+	// Name
+	errField, errOffset = "Name", n
+	p, toRead = 0, int(o.NameLen)
+	if toRead < 0 {
+		return n, &simser.LengthError{Length: toRead, Max: -1}
+	}
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Name = string(b[p : p+toRead])
+	p += toRead
+	if len(o.Name) != int(o.NameLen) {
+		return n, fmt.Errorf("length %d of Name does not match NameLen %d", len(o.Name), o.NameLen)
+	}
+
+	// Pre
+	errField, errOffset = "Pre", n
+	p, toRead = 0, 2
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	{
+		strLen := uint16(b[p])<<8 | uint16(b[p+1])
+		p += 2
+		p, toRead = 0, int(strLen)
+	}
+	if toRead < 0 {
+		return n, &simser.LengthError{Length: toRead, Max: -1}
+	}
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Pre = Label(b[:toRead])
+	p += toRead
+
+	// Fixed
+	errField, errOffset = "Fixed", n
+	p, toRead = 0, 6
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Fixed = string(bytes.TrimRight(b[p:p+6], "\x00"))
+	p += 6
+
+	// C
+	errField, errOffset = "C", n
+	b = src[n:]
+	if len(b) > 9 {
+		b = b[:9]
+	}
+	toRead = bytes.IndexByte(b, 0)
+	if toRead < 0 {
+		if len(b) > 8 {
+			return n, fmt.Errorf("string is not NUL-terminated within 9 bytes")
+		}
+		return n, io.ErrUnexpectedEOF
+	}
+	o.C = string(b[:toRead])
+	n += toRead + 1
+
+	// Labels
+	errField, errOffset = "Labels", n
+	p, toRead = 0, 7
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	for i := 0; i < len(o.Labels); i++ {
+		o.Labels[i] = Label(bytes.TrimRight(b[p:p+3], "\x00"))
+		p += 3
+	}
+
+	// Tail
+	errField, errOffset = "Tail", n-toRead+p
+	o.Tail = uint8(b[p])
+	p += 1
+
+	return n, err
+}
+
+func (o *Strs) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Strs) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Strs: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Strs)(nil)
+	_ encoding.BinaryUnmarshaler = (*Strs)(nil)
+)
+
+func (o *Strs) MarshalTo(dst []byte) (n int, err error) {
+	b := dst[:0:len(dst)]
+
+	// NameLen
+	if uint64(len(o.Name)) > 0xFF {
+		return 0, fmt.Errorf("Name: length %d overflows uint8", len(o.Name))
+	}
+	b = append(b, byte(uint8(len(o.Name))))
+
+	// Name
+	b = append(b, o.Name...)
+
+	// Pre
+	if uint64(len(o.Pre)) > 0xFFFF {
+		return 0, fmt.Errorf("Pre: string length %d overflows uint16 length prefix", len(o.Pre))
+	}
+	b = append(b, byte(uint16(len(o.Pre))>>8), byte(uint16(len(o.Pre))))
+	b = append(b, o.Pre...)
+
+	// Fixed
+	if len(o.Fixed) > 6 {
+		return 0, fmt.Errorf("Fixed: string length %d exceeds 6", len(o.Fixed))
+	}
+	b = append(b, o.Fixed...)
+	b = append(b, make([]byte, 6-len(o.Fixed))...)
+
+	// C
+	if len(o.C) > 8 {
+		return 0, fmt.Errorf("C: string length %d exceeds 8", len(o.C))
+	}
+	if strings.IndexByte(o.C, 0) >= 0 {
+		return 0, fmt.Errorf("C: string contains NUL byte")
+	}
+	b = append(b, o.C...)
+	b = append(b, 0)
+
+	// Labels
+	for i := 0; i < len(o.Labels); i++ {
+		if len(o.Labels[i]) > 3 {
+			return 0, fmt.Errorf("Labels[i]: string length %d exceeds 3", len(o.Labels[i]))
+		}
+		b = append(b, o.Labels[i]...)
+		b = append(b, make([]byte, 3-len(o.Labels[i]))...)
+	}
+
+	// Tail
+	b = append(b, byte(o.Tail))
+	if len(b) > len(dst) {
+		return 0, io.ErrShortBuffer
+	}
+	return len(b), nil
+}
+
+func (o *Strs) AppendBinary(dst []byte) ([]byte, error) {
+	size := o.EncodedSize()
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Strs) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, o.EncodedSize())
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Strs) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Strs) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Strs)(nil)
+	_ encoding.BinaryMarshaler = (*Strs)(nil)
+)
+
+// BoolsEncodedSize is the size of serialized Bools, in bytes.
+const BoolsEncodedSize = 4
+
+func (o *Bools) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Bools", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// A
+	errField, errOffset = "A", n
+	p, toRead = 0, 4
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.A = b[p] != 0
+	p += 1
+
+	// B
+	errField, errOffset = "B", n-toRead+p
+	o.B = Switch(b[p] != 0)
+	p += 1
+
+	// S
+	errField, errOffset = "S", n-toRead+p
+	if b[p] > 1 {
+		return n, fmt.Errorf("invalid bool value %d", b[p])
+	}
+	o.S = b[p] != 0
+	p += 1
+
+	// T
+	errField, errOffset = "T", n-toRead+p
+	if b[p] > 1 {
+		return n, fmt.Errorf("invalid bool value %d", b[p])
+	}
+	o.T = Switch(b[p] != 0)
+	p += 1
+
+	return n, err
+}
+
+func (o *Bools) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Bools", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// A
+	errField, errOffset = "A", n
+	p, toRead = 0, 4
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.A = b[p] != 0
+	p += 1
+
+	// B
+	errField, errOffset = "B", n-toRead+p
+	o.B = Switch(b[p] != 0)
+	p += 1
+
+	// S
+	errField, errOffset = "S", n-toRead+p
+	if b[p] > 1 {
+		return n, fmt.Errorf("invalid bool value %d", b[p])
+	}
+	o.S = b[p] != 0
+	p += 1
+
+	// T
+	errField, errOffset = "T", n-toRead+p
+	if b[p] > 1 {
+		return n, fmt.Errorf("invalid bool value %d", b[p])
+	}
+	o.T = Switch(b[p] != 0)
+	p += 1
+
+	return n, err
+}
+
+func (o *Bools) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Bools) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Bools: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Bools)(nil)
+	_ encoding.BinaryUnmarshaler = (*Bools)(nil)
+)
+
+func (o *Bools) MarshalTo(dst []byte) (n int, err error) {
+	if len(dst) < BoolsEncodedSize {
+		return 0, io.ErrShortBuffer
+	}
+	b := dst[:0]
+
+	// A
+	if o.A {
+		b = append(b, 1)
+	} else {
+		b = append(b, 0)
+	}
+
+	// B
+	if o.B {
+		b = append(b, 1)
+	} else {
+		b = append(b, 0)
+	}
+
+	// S
+	if o.S {
+		b = append(b, 1)
+	} else {
+		b = append(b, 0)
+	}
+
+	// T
+	if o.T {
+		b = append(b, 1)
+	} else {
+		b = append(b, 0)
+	}
+	return len(b), nil
+}
+
+func (o *Bools) AppendBinary(dst []byte) ([]byte, error) {
+	size := BoolsEncodedSize
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Bools) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, BoolsEncodedSize)
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Bools) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Bools) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Bools)(nil)
+	_ encoding.BinaryMarshaler = (*Bools)(nil)
+)
+
+// ConstsEncodedSize is the size of serialized Consts, in bytes.
+const ConstsEncodedSize = 14
+
+func (o *Consts) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Consts", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// Magic
+	errField, errOffset = "Magic", n
+	p, toRead = 0, 14
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	for i := 0; i < len(o.Magic); i++ {
+		o.Magic[i] = byte(b[p])
+		p += 1
+	}
+	if o.Magic != [4]byte{0x52, 0x49, 0x46, 0x46} {
+		return n, &simser.ConstMismatchError{Type: "Consts", Field: "Magic", Offset: n - toRead + p - 4, Expected: [4]byte{0x52, 0x49, 0x46, 0x46}, Actual: o.Magic}
+	}
+
+	// Version
+	errField, errOffset = "Version", n-toRead+p
+	o.Version = uint16(b[p])<<8 | uint16(b[p+1])
+	p += 2
+	if o.Version != uint16(0x0102) {
+		return n, &simser.ConstMismatchError{Type: "Consts", Field: "Version", Offset: n - toRead + p - 2, Expected: uint16(0x0102), Actual: o.Version}
+	}
+
+	// Tag
+	errField, errOffset = "Tag", n-toRead+p
+	for i := 0; i < len(o.Tag); i++ {
+		o.Tag[i] = byte(b[p])
+		p += 1
+	}
+	if o.Tag != [4]byte{0x57, 0x41, 0x56, 0x45} {
+		return n, &simser.ConstMismatchError{Type: "Consts", Field: "Tag", Offset: n - toRead + p - 4, Expected: [4]byte{0x57, 0x41, 0x56, 0x45}, Actual: o.Tag}
+	}
+
+	// Size
+	errField, errOffset = "Size", n-toRead+p
+	o.Size = uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24
+	p += 4
+
+	return n, err
+}
+
+func (o *Consts) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Consts", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// Magic
+	errField, errOffset = "Magic", n
+	p, toRead = 0, 14
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	for i := 0; i < len(o.Magic); i++ {
+		o.Magic[i] = byte(b[p])
+		p += 1
+	}
+	if o.Magic != [4]byte{0x52, 0x49, 0x46, 0x46} {
+		return n, &simser.ConstMismatchError{Type: "Consts", Field: "Magic", Offset: n - toRead + p - 4, Expected: [4]byte{0x52, 0x49, 0x46, 0x46}, Actual: o.Magic}
+	}
+
+	// Version
+	errField, errOffset = "Version", n-toRead+p
+	o.Version = uint16(b[p])<<8 | uint16(b[p+1])
+	p += 2
+	if o.Version != uint16(0x0102) {
+		return n, &simser.ConstMismatchError{Type: "Consts", Field: "Version", Offset: n - toRead + p - 2, Expected: uint16(0x0102), Actual: o.Version}
+	}
+
+	// Tag
+	errField, errOffset = "Tag", n-toRead+p
+	for i := 0; i < len(o.Tag); i++ {
+		o.Tag[i] = byte(b[p])
+		p += 1
+	}
+	if o.Tag != [4]byte{0x57, 0x41, 0x56, 0x45} {
+		return n, &simser.ConstMismatchError{Type: "Consts", Field: "Tag", Offset: n - toRead + p - 4, Expected: [4]byte{0x57, 0x41, 0x56, 0x45}, Actual: o.Tag}
+	}
+
+	// Size
+	errField, errOffset = "Size", n-toRead+p
+	o.Size = uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24
+	p += 4
+
+	return n, err
+}
+
+func (o *Consts) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Consts) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Consts: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Consts)(nil)
+	_ encoding.BinaryUnmarshaler = (*Consts)(nil)
+)
+
+func (o *Consts) MarshalTo(dst []byte) (n int, err error) {
+	if len(dst) < ConstsEncodedSize {
+		return 0, io.ErrShortBuffer
+	}
+	b := dst[:0]
+
+	// Magic
+	b = append(b, 0x52, 0x49, 0x46, 0x46)
+
+	// Version
+	b = append(b, 0x01, 0x02)
+
+	// Tag
+	b = append(b, 0x57, 0x41, 0x56, 0x45)
+
+	// Size
+	b = append(b, byte(o.Size), byte(o.Size>>8), byte(o.Size>>16), byte(o.Size>>24))
+	return len(b), nil
+}
+
+func (o *Consts) AppendBinary(dst []byte) ([]byte, error) {
+	size := ConstsEncodedSize
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Consts) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, ConstsEncodedSize)
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Consts) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Consts) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Consts)(nil)
+	_ encoding.BinaryMarshaler = (*Consts)(nil)
+)
+
+// EncodedSize returns the size of serialized o, in bytes.
+func (o *Limited) EncodedSize() int {
+	return 8 + (4 * len(o.Items)) + (len(o.S)) + (2 * len(o.Big))
+}
+
+func (o *Limited) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	sLen, sElSize := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Limited", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// N
+	errField, errOffset = "N", n
+	p, toRead = 0, 2
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.N = int16(b[p]) | int16(b[p+1])<<8
+	p += 2
+
+	// Items
+	errField, errOffset = "Items", n
+	sLen, sElSize = int(o.N), 4
+	if sLen < 0 || sLen > 4 {
+		return n, &simser.LengthError{Length: sLen, Max: 4}
+	}
+	p, toRead = 0, sLen*sElSize
+	b, nRead, err = simser.ReadFull(r, b, toRead)
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Items = make([]uint32, sLen)
+	for i := 0; i < len(o.Items); i++ {
+		o.Items[i] = uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24
+		p += 4
+	}
+	if len(o.Items) != int(o.N) {
+		return n, fmt.Errorf("length %d of Items does not match N %d", len(o.Items), o.N)
+	}
+
+	// SLen
+	errField, errOffset = "SLen", n
+	p, toRead = 0, 2
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.SLen = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	// S
+	errField, errOffset = "S", n
+	p, toRead = 0, int(o.SLen)
+	if toRead < 0 || toRead > 5 {
+		return n, &simser.LengthError{Length: toRead, Max: 5}
+	}
+	b, nRead, err = simser.ReadFull(r, b, toRead)
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.S = string(b[p : p+toRead])
+	p += toRead
+	if len(o.S) != int(o.SLen) {
+		return n, fmt.Errorf("length %d of S does not match SLen %d", len(o.S), o.SLen)
+	}
+
+	// M
+	errField, errOffset = "M", n
+	p, toRead = 0, 4
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.M = uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24
+	p += 4
+
+	// Big
+	errField, errOffset = "Big", n
+	sLen, sElSize = int(o.M), 2
+	if sLen < 0 || sLen > math.MaxInt/2 {
+		return n, &simser.LengthError{Length: sLen, Max: -1}
+	}
+	p, toRead = 0, sLen*sElSize
+	b, nRead, err = simser.ReadFull(r, b, toRead)
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Big = make([]uint16, sLen)
+	for i := 0; i < len(o.Big); i++ {
+		o.Big[i] = uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+	}
+	if len(o.Big) != int(o.M) {
+		return n, fmt.Errorf("length %d of Big does not match M %d", len(o.Big), o.M)
+	}
+
+	return n, err
+}
+
+func (o *Limited) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	sLen, sElSize := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Limited", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// N
+	errField, errOffset = "N", n
+	p, toRead = 0, 2
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.N = int16(b[p]) | int16(b[p+1])<<8
+	p += 2
+
+	// Items
+	errField, errOffset = "Items", n
+	sLen, sElSize = int(o.N), 4
+	if sLen < 0 || sLen > 4 {
+		return n, &simser.LengthError{Length: sLen, Max: 4}
+	}
+	p, toRead = 0, sLen*sElSize
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Items = make([]uint32, sLen)
+	for i := 0; i < len(o.Items); i++ {
+		o.Items[i] = uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24
+		p += 4
+	}
+	if len(o.Items) != int(o.N) {
+		return n, fmt.Errorf("length %d of Items does not match N %d", len(o.Items), o.N)
+	}
+
+	// SLen
+	errField, errOffset = "SLen", n
+	p, toRead = 0, 2
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.SLen = uint16(b[p]) | uint16(b[p+1])<<8
+	p += 2
+
+	// S
+	errField, errOffset = "S", n
+	p, toRead = 0, int(o.SLen)
+	if toRead < 0 || toRead > 5 {
+		return n, &simser.LengthError{Length: toRead, Max: 5}
+	}
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.S = string(b[p : p+toRead])
+	p += toRead
+	if len(o.S) != int(o.SLen) {
+		return n, fmt.Errorf("length %d of S does not match SLen %d", len(o.S), o.SLen)
+	}
+
+	// M
+	errField, errOffset = "M", n
+	p, toRead = 0, 4
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.M = uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24
+	p += 4
+
+	// Big
+	errField, errOffset = "Big", n
+	sLen, sElSize = int(o.M), 2
+	if sLen < 0 || sLen > math.MaxInt/2 {
+		return n, &simser.LengthError{Length: sLen, Max: -1}
+	}
+	p, toRead = 0, sLen*sElSize
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Big = make([]uint16, sLen)
+	for i := 0; i < len(o.Big); i++ {
+		o.Big[i] = uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+	}
+	if len(o.Big) != int(o.M) {
+		return n, fmt.Errorf("length %d of Big does not match M %d", len(o.Big), o.M)
+	}
+
+	return n, err
+}
+
+func (o *Limited) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Limited) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Limited: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Limited)(nil)
+	_ encoding.BinaryUnmarshaler = (*Limited)(nil)
+)
+
+func (o *Limited) MarshalTo(dst []byte) (n int, err error) {
+	b := dst[:0:len(dst)]
+
+	// N
+	if uint64(len(o.Items)) > 0x7FFF {
+		return 0, fmt.Errorf("Items: length %d overflows int16", len(o.Items))
+	}
+	b = append(b, byte(int16(len(o.Items))), byte(int16(len(o.Items))>>8))
+
+	// Items
+	for i := 0; i < len(o.Items); i++ {
+		b = append(b, byte(o.Items[i]), byte(o.Items[i]>>8), byte(o.Items[i]>>16), byte(o.Items[i]>>24))
+	}
+
+	// SLen
+	if uint64(len(o.S)) > 0xFFFF {
+		return 0, fmt.Errorf("S: length %d overflows uint16", len(o.S))
+	}
+	b = append(b, byte(uint16(len(o.S))), byte(uint16(len(o.S))>>8))
+
+	// S
+	b = append(b, o.S...)
+
+	// M
+	if uint64(len(o.Big)) > 0xFFFFFFFF {
+		return 0, fmt.Errorf("Big: length %d overflows uint32", len(o.Big))
+	}
+	b = append(b, byte(uint32(len(o.Big))), byte(uint32(len(o.Big))>>8), byte(uint32(len(o.Big))>>16), byte(uint32(len(o.Big))>>24))
+
+	// Big
+	for i := 0; i < len(o.Big); i++ {
+		b = append(b, byte(o.Big[i]), byte(o.Big[i]>>8))
+	}
+	if len(b) > len(dst) {
+		return 0, io.ErrShortBuffer
+	}
+	return len(b), nil
+}
+
+func (o *Limited) AppendBinary(dst []byte) ([]byte, error) {
+	size := o.EncodedSize()
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Limited) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, o.EncodedSize())
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Limited) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Limited) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Limited)(nil)
+	_ encoding.BinaryMarshaler = (*Limited)(nil)
+)
+
+// EncodedSize returns the size of serialized o, in bytes.
+func (o *Varints) EncodedSize() int {
+	return 0 + (simser.UvarintSize(uint64(o.U8))) + (simser.VarintSize(int64(o.I16))) + (simser.UvarintSize(uint64(len(o.Items)))) + (simser.VarintSliceSize(o.Items)) + (simser.UvarintSize(uint64(len(o.S))) + len(o.S))
+}
+
+func (o *Varints) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	sLen := 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Varints", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// U8
+	errField, errOffset = "U8", n
+	{
+		var v uint64
+		b, toRead, err = simser.ReadVarint(r, b)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		if v, _, err = simser.Uvarint(b[:toRead], 8); err != nil {
+			return n, err
+		}
+		o.U8 = uint8(v)
+	}
+
+	// I16
+	errField, errOffset = "I16", n
+	{
+		var v int64
+		b, toRead, err = simser.ReadVarint(r, b)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		if v, _, err = simser.Varint(b[:toRead], 16); err != nil {
+			return n, err
+		}
+		o.I16 = int16(v)
+	}
+
+	// N
+	errField, errOffset = "N", n
+	{
+		var v uint64
+		b, toRead, err = simser.ReadVarint(r, b)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		if v, _, err = simser.Uvarint(b[:toRead], 32); err != nil {
+			return n, err
+		}
+		o.N = uint32(v)
+	}
+
+	// Items
+	errField, errOffset = "Items", n
+	sLen = int(o.N)
+	if sLen < 0 || sLen > math.MaxInt/8 {
+		return n, &simser.LengthError{Length: sLen, Max: -1}
+	}
+	o.Items = make([]int64, 0)
+	for i := 0; i < sLen; i++ {
+		var v int64
+		b, toRead, err = simser.ReadVarint(r, b)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		if v, _, err = simser.Varint(b[:toRead], 64); err != nil {
+			return n, err
+		}
+		o.Items = append(o.Items, int64(v))
+	}
+	if len(o.Items) != int(o.N) {
+		return n, fmt.Errorf("length %d of Items does not match N %d", len(o.Items), o.N)
+	}
+
+	// S
+	errField, errOffset = "S", n
+	{
+		var v uint64
+		b, toRead, err = simser.ReadVarint(r, b)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		if v, _, err = simser.Uvarint(b[:toRead], 64); err != nil {
+			return n, err
+		}
+		p, toRead = 0, int(v)
+	}
+	if toRead < 0 {
+		return n, &simser.LengthError{Length: toRead, Max: -1}
+	}
+	b, nRead, err = simser.ReadFull(r, b, toRead)
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.S = string(b[:toRead])
+	p += toRead
+
+	return n, err
+}
+
+func (o *Varints) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	sLen := 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Varints", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// U8
+	errField, errOffset = "U8", n
+	{
+		var v uint64
+		v, toRead, err = simser.Uvarint(src[n:], 8)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		o.U8 = uint8(v)
+	}
+
+	// I16
+	errField, errOffset = "I16", n
+	{
+		var v int64
+		v, toRead, err = simser.Varint(src[n:], 16)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		o.I16 = int16(v)
+	}
+
+	// N
+	errField, errOffset = "N", n
+	{
+		var v uint64
+		v, toRead, err = simser.Uvarint(src[n:], 32)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		o.N = uint32(v)
+	}
+
+	// Items
+	errField, errOffset = "Items", n
+	sLen = int(o.N)
+	if sLen < 0 || sLen > math.MaxInt/8 {
+		return n, &simser.LengthError{Length: sLen, Max: -1}
+	}
+	if sLen > len(src)-n {
+		return n, io.ErrUnexpectedEOF
+	}
+	o.Items = make([]int64, sLen)
+	for i := 0; i < len(o.Items); i++ {
+		var v int64
+		v, toRead, err = simser.Varint(src[n:], 64)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		o.Items[i] = int64(v)
+	}
+	if len(o.Items) != int(o.N) {
+		return n, fmt.Errorf("length %d of Items does not match N %d", len(o.Items), o.N)
+	}
+
+	// S
+	errField, errOffset = "S", n
+	{
+		var v uint64
+		v, toRead, err = simser.Uvarint(src[n:], 64)
+		n += toRead
+		if err != nil {
+			return n, err
+		}
+		p, toRead = 0, int(v)
+	}
+	if toRead < 0 {
+		return n, &simser.LengthError{Length: toRead, Max: -1}
+	}
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.S = string(b[:toRead])
+	p += toRead
+
+	return n, err
+}
+
+func (o *Varints) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Varints) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Varints: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Varints)(nil)
+	_ encoding.BinaryUnmarshaler = (*Varints)(nil)
+)
+
+func (o *Varints) MarshalTo(dst []byte) (n int, err error) {
+	b := dst[:0:len(dst)]
+
+	// U8
+	b = simser.AppendUvarint(b, uint64(o.U8))
+
+	// I16
+	b = simser.AppendVarint(b, int64(o.I16))
+
+	// N
+	if uint64(len(o.Items)) > 0xFFFFFFFF {
+		return 0, fmt.Errorf("Items: length %d overflows uint32", len(o.Items))
+	}
+	b = simser.AppendUvarint(b, uint64(len(o.Items)))
+
+	// Items
+	for i := 0; i < len(o.Items); i++ {
+		b = simser.AppendVarint(b, int64(o.Items[i]))
+	}
+
+	// S
+	b = simser.AppendUvarint(b, uint64(len(o.S)))
+	b = append(b, o.S...)
+	if len(b) > len(dst) {
+		return 0, io.ErrShortBuffer
+	}
+	return len(b), nil
+}
+
+func (o *Varints) AppendBinary(dst []byte) ([]byte, error) {
+	size := o.EncodedSize()
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Varints) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, o.EncodedSize())
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Varints) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Varints) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Varints)(nil)
+	_ encoding.BinaryMarshaler = (*Varints)(nil)
+)
+
+// EncodedSize returns the size of serialized o, in bytes.
+func (o *WireInts) EncodedSize() int {
+	return 15 + (1 * len(o.Is))
+}
+
+func (o *WireInts) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	sLen, sElSize := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "WireInts", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// I
+	errField, errOffset = "I", n
+	p, toRead = 0, 15
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	{
+		v := int16(b[p]) | int16(b[p+1])<<8
+		p += 2
+		o.I = int(v)
+	}
+
+	// U
+	errField, errOffset = "U", n-toRead+p
+	{
+		v := uint8(b[p])
+		p += 1
+		o.U = uint(v)
+	}
+
+	// P
+	errField, errOffset = "P", n-toRead+p
+	{
+		v := uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24
+		p += 4
+		o.P = uintptr(v)
+	}
+
+	// L
+	errField, errOffset = "L", n-toRead+p
+	{
+		v := uint64(b[p]) | uint64(b[p+1])<<8 | uint64(b[p+2])<<16 | uint64(b[p+3])<<24 | uint64(b[p+4])<<32 |
+			uint64(b[p+5])<<40 | uint64(b[p+6])<<48 | uint64(b[p+7])<<56
+		p += 8
+		if v > math.MaxInt {
+			return n, fmt.Errorf("value %d overflows int", v)
+		}
+		o.L = int(v)
+	}
+
+	// Is
+	errField, errOffset = "Is", n
+	sLen, sElSize = 2, 1
+	if sLen < 0 {
+		return n, &simser.LengthError{Length: sLen, Max: -1}
+	}
+	p, toRead = 0, sLen*sElSize
+	b, nRead, err = simser.ReadFull(r, b, toRead)
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Is = make([]int, sLen)
+	for i := 0; i < len(o.Is); i++ {
+		{
+			v := int8(b[p])
+			p += 1
+			o.Is[i] = int(v)
+		}
+	}
+
+	return n, err
+}
+
+func (o *WireInts) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	sLen, sElSize := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "WireInts", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// I
+	errField, errOffset = "I", n
+	p, toRead = 0, 15
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	{
+		v := int16(b[p]) | int16(b[p+1])<<8
+		p += 2
+		o.I = int(v)
+	}
+
+	// U
+	errField, errOffset = "U", n-toRead+p
+	{
+		v := uint8(b[p])
+		p += 1
+		o.U = uint(v)
+	}
+
+	// P
+	errField, errOffset = "P", n-toRead+p
+	{
+		v := uint32(b[p]) | uint32(b[p+1])<<8 | uint32(b[p+2])<<16 | uint32(b[p+3])<<24
+		p += 4
+		o.P = uintptr(v)
+	}
+
+	// L
+	errField, errOffset = "L", n-toRead+p
+	{
+		v := uint64(b[p]) | uint64(b[p+1])<<8 | uint64(b[p+2])<<16 | uint64(b[p+3])<<24 | uint64(b[p+4])<<32 |
+			uint64(b[p+5])<<40 | uint64(b[p+6])<<48 | uint64(b[p+7])<<56
+		p += 8
+		if v > math.MaxInt {
+			return n, fmt.Errorf("value %d overflows int", v)
+		}
+		o.L = int(v)
+	}
+
+	// Is
+	errField, errOffset = "Is", n
+	sLen, sElSize = 2, 1
+	if sLen < 0 {
+		return n, &simser.LengthError{Length: sLen, Max: -1}
+	}
+	p, toRead = 0, sLen*sElSize
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Is = make([]int, sLen)
+	for i := 0; i < len(o.Is); i++ {
+		{
+			v := int8(b[p])
+			p += 1
+			o.Is[i] = int(v)
+		}
+	}
+
+	return n, err
+}
+
+func (o *WireInts) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *WireInts) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("WireInts: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*WireInts)(nil)
+	_ encoding.BinaryUnmarshaler = (*WireInts)(nil)
+)
+
+func (o *WireInts) MarshalTo(dst []byte) (n int, err error) {
+	b := dst[:0:len(dst)]
+
+	// I
+	if o.I < -32768 || o.I > 32767 {
+		return 0, fmt.Errorf("I: value %d overflows int16", o.I)
+	}
+	b = append(b, byte(int16(o.I)), byte(int16(o.I)>>8))
+
+	// U
+	if uint64(o.U) > 0xFF {
+		return 0, fmt.Errorf("U: value %d overflows uint8", o.U)
+	}
+	b = append(b, byte(uint8(o.U)))
+
+	// P
+	if uint64(o.P) > 0xFFFFFFFF {
+		return 0, fmt.Errorf("P: value %d overflows uint32", o.P)
+	}
+	b = append(b, byte(uint32(o.P)), byte(uint32(o.P)>>8), byte(uint32(o.P)>>16), byte(uint32(o.P)>>24))
+
+	// L
+	if o.L < 0 {
+		return 0, fmt.Errorf("L: value %d overflows uint64", o.L)
+	}
+	b = append(b, byte(uint64(o.L)), byte(uint64(o.L)>>8), byte(uint64(o.L)>>16), byte(uint64(o.L)>>24), byte(uint64(o.L)>>32), byte(uint64(o.L)>>40), byte(uint64(o.L)>>48), byte(uint64(o.L)>>56))
+
+	// Is
+	for i := 0; i < len(o.Is); i++ {
+		if o.Is[i] < -128 || o.Is[i] > 127 {
+			return 0, fmt.Errorf("Is[i]: value %d overflows int8", o.Is[i])
+		}
+		b = append(b, byte(int8(o.Is[i])))
+	}
+	if len(b) > len(dst) {
+		return 0, io.ErrShortBuffer
+	}
+	return len(b), nil
+}
+
+func (o *WireInts) AppendBinary(dst []byte) ([]byte, error) {
+	size := o.EncodedSize()
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *WireInts) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, o.EncodedSize())
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *WireInts) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *WireInts) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*WireInts)(nil)
+	_ encoding.BinaryMarshaler = (*WireInts)(nil)
+)
+
+// EncodedSize returns the size of serialized o, in bytes.
+func (o *Mix) EncodedSize() int {
+	size := 1 + (2 * len(o.Names))
+	// S
+	if uint8(len(o.Names)) > 0 {
+		size += simser.UvarintSize(uint64(len(o.S))) + len(o.S)
+	}
+	return size
+}
+
+func (o *Mix) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	sLen, sElSize := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Mix", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// N
+	errField, errOffset = "N", n
+	p, toRead = 0, 1
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.N = uint8(b[p])
+	p += 1
+
+	// Names
+	errField, errOffset = "Names", n
+	sLen, sElSize = int(o.N), 2
+	if sLen < 0 || sLen > math.MaxInt/2 {
+		return n, &simser.LengthError{Length: sLen, Max: -1}
+	}
+	p, toRead = 0, sLen*sElSize
+	b, nRead, err = simser.ReadFull(r, b, toRead)
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	o.Names = make([]uint16, sLen)
+	for i := 0; i < len(o.Names); i++ {
+		o.Names[i] = uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+	}
+	if len(o.Names) != int(o.N) {
+		return n, fmt.Errorf("length %d of Names does not match N %d", len(o.Names), o.N)
+	}
+
+	// S
+	if o.N > 0 {
+		errField, errOffset = "S", n
+		{
+			var v uint64
+			b, toRead, err = simser.ReadVarint(r, b)
+			n += toRead
+			if err != nil {
+				return n, err
+			}
+			if v, _, err = simser.Uvarint(b[:toRead], 64); err != nil {
+				return n, err
+			}
+			p, toRead = 0, int(v)
+		}
+		if toRead < 0 {
+			return n, &simser.LengthError{Length: toRead, Max: -1}
+		}
+		b, nRead, err = simser.ReadFull(r, b, toRead)
+		n += nRead
+		if err != nil {
+			return n, err
+		}
+		o.S = string(b[:toRead])
+		p += toRead
+	} else {
+		o.S = *new(string)
+	}
+
+	return n, err
+}
+
+func (o *Mix) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	sLen, sElSize := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Mix", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// N
+	errField, errOffset = "N", n
+	p, toRead = 0, 1
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.N = uint8(b[p])
+	p += 1
+
+	// Names
+	errField, errOffset = "Names", n
+	sLen, sElSize = int(o.N), 2
+	if sLen < 0 || sLen > math.MaxInt/2 {
+		return n, &simser.LengthError{Length: sLen, Max: -1}
+	}
+	p, toRead = 0, sLen*sElSize
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	o.Names = make([]uint16, sLen)
+	for i := 0; i < len(o.Names); i++ {
+		o.Names[i] = uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+	}
+	if len(o.Names) != int(o.N) {
+		return n, fmt.Errorf("length %d of Names does not match N %d", len(o.Names), o.N)
+	}
+
+	// S
+	if o.N > 0 {
+		errField, errOffset = "S", n
+		{
+			var v uint64
+			v, toRead, err = simser.Uvarint(src[n:], 64)
+			n += toRead
+			if err != nil {
+				return n, err
+			}
+			p, toRead = 0, int(v)
+		}
+		if toRead < 0 {
+			return n, &simser.LengthError{Length: toRead, Max: -1}
+		}
+		if len(src)-n < toRead {
+			return n, io.ErrUnexpectedEOF
+		}
+		b = src[n : n+toRead]
+		n += toRead
+		o.S = string(b[:toRead])
+		p += toRead
+	} else {
+		o.S = *new(string)
+	}
+
+	return n, err
+}
+
+func (o *Mix) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Mix) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Mix: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Mix)(nil)
+	_ encoding.BinaryUnmarshaler = (*Mix)(nil)
+)
+
+func (o *Mix) MarshalTo(dst []byte) (n int, err error) {
+	b := dst[:0:len(dst)]
+
+	// N
+	if uint64(len(o.Names)) > 0xFF {
+		return 0, fmt.Errorf("Names: length %d overflows uint8", len(o.Names))
+	}
+	b = append(b, byte(uint8(len(o.Names))))
+
+	// Names
+	for i := 0; i < len(o.Names); i++ {
+		b = append(b, byte(o.Names[i]), byte(o.Names[i]>>8))
+	}
+
+	// S
+	if uint8(len(o.Names)) > 0 {
+		b = simser.AppendUvarint(b, uint64(len(o.S)))
+		b = append(b, o.S...)
+	}
+	if len(b) > len(dst) {
+		return 0, io.ErrShortBuffer
+	}
+	return len(b), nil
+}
+
+func (o *Mix) AppendBinary(dst []byte) ([]byte, error) {
+	size := o.EncodedSize()
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Mix) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, o.EncodedSize())
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Mix) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Mix) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Mix)(nil)
+	_ encoding.BinaryMarshaler = (*Mix)(nil)
+)
+
+// PackedEncodedSize is the size of serialized Packed, in bytes.
+const PackedEncodedSize = 6
+
+func (o *Packed) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	p, nRead, toRead := 0, 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Packed", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// A|B|C
+	errField, errOffset = "A|B|C", n
+	p, toRead = 0, 6
+	if toRead > cap(b) {
+		b = make([]byte, toRead)
+	}
+	nRead, err = io.ReadFull(r, b[:toRead])
+	n += nRead
+	if err != nil {
+		return n, err
+	}
+	{
+		c := uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+		o.A = uint8(c & 0xF)
+		o.B = uint8((c >> 4) & 0xF)
+		o.C = uint8(c >> 8)
+	}
+
+	// D|E|F
+	errField, errOffset = "D|E|F", n-toRead+p
+	{
+		c := uint32(b[p])<<24 | uint32(b[p+1])<<16 | uint32(b[p+2])<<8 | uint32(b[p+3])
+		p += 4
+		o.D = uint8(c >> 24)
+		o.E = uint16(c >> 8)
+		o.F = uint8(c)
+	}
+
+	return n, err
+}
+
+func (o *Packed) DecodeFrom(src []byte) (n int, err error) {
+	var b []byte
+	p, toRead := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Packed", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// A|B|C
+	errField, errOffset = "A|B|C", n
+	p, toRead = 0, 6
+	if len(src)-n < toRead {
+		return n, io.ErrUnexpectedEOF
+	}
+	b = src[n : n+toRead]
+	n += toRead
+	{
+		c := uint16(b[p]) | uint16(b[p+1])<<8
+		p += 2
+		o.A = uint8(c & 0xF)
+		o.B = uint8((c >> 4) & 0xF)
+		o.C = uint8(c >> 8)
+	}
+
+	// D|E|F
+	errField, errOffset = "D|E|F", n-toRead+p
+	{
+		c := uint32(b[p])<<24 | uint32(b[p+1])<<16 | uint32(b[p+2])<<8 | uint32(b[p+3])
+		p += 4
+		o.D = uint8(c >> 24)
+		o.E = uint16(c >> 8)
+		o.F = uint8(c)
+	}
+
+	return n, err
+}
+
+func (o *Packed) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Packed) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Packed: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Packed)(nil)
+	_ encoding.BinaryUnmarshaler = (*Packed)(nil)
+)
+
+func (o *Packed) MarshalTo(dst []byte) (n int, err error) {
+	if len(dst) < PackedEncodedSize {
+		return 0, io.ErrShortBuffer
+	}
+	b := dst[:0]
+
+	// A|B|C
+	{
+		if o.A > 0xF {
+			return 0, fmt.Errorf("A: value %d overflows 4 bits", o.A)
+		}
+		if o.B > 0xF {
+			return 0, fmt.Errorf("B: value %d overflows 4 bits", o.B)
+		}
+		var c uint16
+		c |= uint16(o.A)
+		c |= uint16(o.B) << 4
+		c |= uint16(o.C) << 8
+		b = append(b, byte(c), byte(c>>8))
+	}
+
+	// D|E|F
+	{
+		var c uint32
+		c |= uint32(o.D) << 24
+		c |= uint32(o.E) << 8
+		c |= uint32(o.F)
+		b = append(b, byte(c>>24), byte(c>>16), byte(c>>8), byte(c))
+	}
+	return len(b), nil
+}
+
+func (o *Packed) AppendBinary(dst []byte) ([]byte, error) {
+	size := PackedEncodedSize
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Packed) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, PackedEncodedSize)
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Packed) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Packed) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Packed)(nil)
+	_ encoding.BinaryMarshaler = (*Packed)(nil)
+)
+
+// EncodedSize returns the size of serialized o, in bytes.
+func (o *Plain) EncodedSize() int {
+	return 0 + (len(plainWrite(nil, o.V)))
+}
+
+func (o *Plain) LoadFrom(r io.Reader) (n int, err error) {
+	var b []byte
+	nRead, toRead := 0, 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Plain", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// V
+	errField, errOffset = "V", n
+	{
+		k := 0
+		toRead = 0
+		for {
+			o.V, k, err = plainRead(b[:toRead])
+			if err == nil {
+				break
+			}
+			if err != io.ErrUnexpectedEOF {
+				return n, err
+			}
+			if k <= toRead {
+				return n, fmt.Errorf("plainRead returned io.ErrUnexpectedEOF, asking for %d bytes, not more than %d it got", k, toRead)
+			}
+			if k > 4096 {
+				return n, fmt.Errorf("encoded value exceeds 4096 bytes")
+			}
+			if k > cap(b) {
+				b = append(b[:toRead], make([]byte, k-toRead)...)
+			}
+			nRead, err = io.ReadFull(r, b[toRead:k])
+			n += nRead
+			if err != nil {
+				return n, err
+			}
+			toRead = k
+		}
+		if k != toRead {
+			return n, fmt.Errorf("plainRead reported %d bytes read, out of %d", k, toRead)
+		}
+	}
+
+	return n, err
+}
+
+func (o *Plain) DecodeFrom(src []byte) (n int, err error) {
+	toRead := 0
+	errField, errOffset := "", 0
+	defer func() {
+		if err == nil || (err == io.EOF && n == 0) {
+			return
+		}
+		if _, ok := err.(*simser.ConstMismatchError); ok {
+			return
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = &simser.FieldError{Type: "Plain", Field: errField, Offset: errOffset, Err: err}
+	}()
+
+	// V
+	errField, errOffset = "V", n
+	o.V, toRead, err = plainRead(src[n:])
+	if err != nil {
+		return n, err
+	}
+	if toRead < 0 || toRead > len(src)-n {
+		return n, fmt.Errorf("plainRead reported %d bytes read, out of %d", toRead, len(src)-n)
+	}
+	n += toRead
+
+	return n, err
+}
+
+func (o *Plain) ReadFrom(r io.Reader) (int64, error) {
+	n, err := o.LoadFrom(r)
+	return int64(n), err
+}
+
+func (o *Plain) UnmarshalBinary(data []byte) error {
+	n, err := o.DecodeFrom(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("Plain: %d bytes left after unmarshaling", len(data)-n)
+	}
+	return nil
+}
+
+var (
+	_ io.ReaderFrom              = (*Plain)(nil)
+	_ encoding.BinaryUnmarshaler = (*Plain)(nil)
+)
+
+func (o *Plain) MarshalTo(dst []byte) (n int, err error) {
+	b := dst[:0:len(dst)]
+
+	// V
+	b = plainWrite(b, o.V)
+	if len(b) > len(dst) {
+		return 0, io.ErrShortBuffer
+	}
+	return len(b), nil
+}
+
+func (o *Plain) AppendBinary(dst []byte) ([]byte, error) {
+	size := o.EncodedSize()
+	if cap(dst)-len(dst) < size {
+		dst = append(dst, make([]byte, size)...)[:len(dst)]
+	}
+	n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])
+	if err != nil {
+		return dst, err
+	}
+	return dst[:len(dst)+n], nil
+}
+
+func (o *Plain) SaveTo(w io.Writer) (n int, err error) {
+	b := make([]byte, o.EncodedSize())
+	if n, err = o.MarshalTo(b); err != nil {
+		return 0, err
+	}
+	return w.Write(b[:n])
+}
+
+func (o *Plain) WriteTo(w io.Writer) (int64, error) {
+	n, err := o.SaveTo(w)
+	return int64(n), err
+}
+
+func (o *Plain) MarshalBinary() ([]byte, error) {
+	return o.AppendBinary(nil)
+}
+
+var (
+	_ io.WriterTo              = (*Plain)(nil)
+	_ encoding.BinaryMarshaler = (*Plain)(nil)
+)
@@ -0,0 +1,50 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import "testing"
+
+func TestSkip(t *testing.T) {
+	testRoundTrips(t, []roundTrip{
+		{
+			name: "skip modes",
+			in:   &Skips{A: 1, Gone: 2, R: 0x0304, W: 0x0506},
+			data: []byte{0x01, 0x04, 0x03, 0x00, 0x00},
+			out:  &Skips{},
+			want: &Skips{A: 1},
+		},
+	})
+
+	// Read-only field is read, write-only one is left untouched
+	for _, r := range readers {
+		out := &Skips{Gone: 2, R: 7}
+		if err := r.read(out, []byte{0x01, 0x04, 0x03, 0x06, 0x05}); err != nil {
+			t.Fatalf("%s(): %v", r.name, err)
+		}
+		if want := (Skips{A: 1, Gone: 2, R: 7, W: 0x0506}); *out != want {
+			t.Fatalf("%s() = %+v, want %+v", r.name, *out, want)
+		}
+	}
+
+	testReadErrors(t, []readError{
+		{
+			// Fixed-size fields are read at once
+			name: "truncated read-only field",
+			data: []byte{0x01, 0x04},
+			out:  &Skips{},
+			err:  "Skips.A at offset 0: unexpected EOF",
+		},
+	})
+}
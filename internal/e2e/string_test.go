@@ -0,0 +1,126 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrings(t *testing.T) {
+	testRoundTrips(t, []roundTrip{
+		{
+			name: "encodings",
+			in:   &Strs{Name: "ab", Pre: "héllo", Fixed: "xyz", C: "nul", Labels: [2]Label{"a", "bcd"}, Tail: 9},
+			data: []byte{
+				0x02, 'a', 'b', // NameLen, Name
+				0x00, 0x06, 'h', 0xC3, 0xA9, 'l', 'l', 'o', // Pre
+				'x', 'y', 'z', 0, 0, 0, // Fixed
+				'n', 'u', 'l', 0, // C
+				'a', 0, 0, 'b', 'c', 'd', // Labels
+				0x09, // Tail
+			},
+			out:  &Strs{},
+			want: &Strs{NameLen: 2, Name: "ab", Pre: "héllo", Fixed: "xyz", C: "nul", Labels: [2]Label{"a", "bcd"}, Tail: 9},
+		},
+		{
+			name: "empty and full",
+			in:   &Strs{Fixed: "abcdef", C: "12345678"},
+			data: []byte{
+				0x00,       // NameLen
+				0x00, 0x00, // Pre
+				'a', 'b', 'c', 'd', 'e', 'f', // Fixed
+				'1', '2', '3', '4', '5', '6', '7', '8', 0, // C
+				0, 0, 0, 0, 0, 0, // Labels
+				0x00, // Tail
+			},
+			out: &Strs{},
+		},
+	})
+
+	// Strs with data of C field replaced
+	withC := func(c ...byte) []byte {
+		data := []byte{0x00, 0x00, 0x00, 0, 0, 0, 0, 0, 0}
+		return append(data, c...)
+	}
+	testReadErrors(t, []readError{
+		{
+			name: "cstr without terminator within max",
+			data: withC([]byte("123456789\x00")...),
+			out:  &Strs{},
+			err:  "Strs.C at offset 9: string is not NUL-terminated within 9 bytes",
+		},
+		{
+			name: "cstr without terminator",
+			data: withC([]byte("1234")...),
+			out:  &Strs{},
+			err:  "Strs.C at offset 9: unexpected EOF",
+		},
+		{
+			name: "truncated length",
+			data: []byte{0x03, 'a', 'b'},
+			out:  &Strs{},
+			err:  "Strs.Name at offset 1: unexpected EOF",
+		},
+		{
+			name: "truncated prefixed",
+			data: []byte{0x00, 0x00, 0x05, 'a'},
+			out:  &Strs{},
+			err:  "Strs.Pre at offset 1: unexpected EOF",
+		},
+	})
+
+	testWriteErrors(t, []writeError{
+		{
+			name: "fixed longer than field",
+			in:   &Strs{Fixed: "abcdefg"},
+			err:  "Fixed: string length 7 exceeds 6",
+		},
+		{
+			name: "fixed array element longer than field",
+			in:   &Strs{Labels: [2]Label{"", "abcd"}},
+			err:  "Labels[i]: string length 4 exceeds 3",
+		},
+		{
+			name: "cstr longer than max",
+			in:   &Strs{C: "123456789"},
+			err:  "C: string length 9 exceeds 8",
+		},
+		{
+			name: "cstr with NUL",
+			in:   &Strs{C: "a\x00b"},
+			err:  "C: string contains NUL byte",
+		},
+		{
+			name: "prefix overflow",
+			in:   &Strs{Pre: Label(strings.Repeat("a", 1<<16))},
+			err:  "Pre: string length 65536 overflows uint16 length prefix",
+		},
+	})
+}
+
+// Trailing zeros of fixed strings are trimmed on read
+func TestFixedStringTrim(t *testing.T) {
+	data := []byte{0x00, 0x00, 0x00, 'x', 0, 'y', 0, 0, 0, 0, 'a', 0, 0, 0, 0, 0, 0}
+	for _, r := range readers {
+		out := &Strs{}
+		if err := r.read(out, data); err != nil {
+			t.Fatalf("%s(): %v", r.name, err)
+		}
+		if out.Fixed != "x\x00y" || out.Labels[0] != "a" {
+			t.Fatalf("%s() = %+v, want Fixed %q, Labels[0] %q", r.name, *out, "x\x00y", "a")
+		}
+	}
+}
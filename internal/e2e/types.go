@@ -0,0 +1,178 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package e2e holds types, serialized by generated code, for end-to-end tests of simser.
+package e2e
+
+//go:generate go run github.com/amanofbits/simser -pkg -types=all -bytes -interfaces=std
+
+// Explicit length field
+type Counted struct {
+	Count uint16   `simser:"lenof=Items"`
+	Items []uint32 `simser:"len=int(o.Count)"`
+}
+
+// Fixed-size struct, [de]serialized without allocations by byte slice methods
+type Fixed struct {
+	Magic   uint32 `simser:"const=0x44584946"`
+	Version uint8  `simser:"bits=4"`
+	Flags   uint8  `simser:"bits=4"`
+	_       [3]byte
+	Skipped uint32 `simser:"rskip"`
+	Pos     [3]float32
+	ID      int64 `simser:"order=be"`
+	Ok      bool
+}
+
+// Fields, which are only skipped on read
+type Skipped struct {
+	_ [4]byte
+	X uint32 `simser:"rskip"`
+}
+
+// Variable-size struct, with strings and optional fields
+type Message struct {
+	Flags uint8
+	Seq   uint64  `simser:"varint"`
+	Delta int32   `simser:"zigzag"`
+	Topic string  `simser:"cstr,max=64"`
+	Body  string  `simser:"prefix=u16"`
+	Score float64 `simser:"if=o.Flags&1 != 0"`
+}
+
+// Field, encoded by functions of the package
+type Coded struct {
+	Kind  uint8
+	Words []string `simser:"codec=words,max=1024"`
+	Tail  uint16
+}
+
+// Nested struct, with field of its own byte order
+type Pair struct {
+	A uint16
+	B uint16 `simser:"order=le"`
+}
+
+// Nested structs, and arrays and slices of them
+type Shape struct {
+	Origin Pair
+	Corner Pair `simser:"order=be"`
+	Inline struct {
+		X int8
+		Y int16 `simser:"order=be"`
+	}
+	Arr [2]Pair
+	N   uint8
+	Pts []Pair `simser:"len=int(o.N)"`
+}
+
+// Fields, skipped entirely, on read or on write
+type Skips struct {
+	A    uint8
+	Gone uint32 `simser:"skip"`
+	R    uint16 `simser:"rskip"`
+	W    uint16 `simser:"wskip"`
+}
+
+// Padding fields, and write-only field with padding byte
+type Padded struct {
+	A uint8
+	_ [3]byte
+	B uint16
+	_ [2]uint16 `simser:"pad=0xFF"`
+	W uint8     `simser:"wskip,pad=0xEE"`
+}
+
+// Named string type
+type Label string
+
+// String encodings
+type Strs struct {
+	NameLen uint8
+	Name    string   `simser:"len=int(o.NameLen)"`
+	Pre     Label    `simser:"prefix=u16,order=be"`
+	Fixed   string   `simser:"fixed=6"`
+	C       string   `simser:"cstr,max=8"`
+	Labels  [2]Label `simser:"fixed=3"`
+	Tail    uint8
+}
+
+// Named bool type
+type Switch bool
+
+// Lenient and strict bools
+type Bools struct {
+	A bool
+	B Switch
+	S bool   `simser:"strict"`
+	T Switch `simser:"strict"`
+}
+
+// Constant fields
+type Consts struct {
+	Magic   [4]byte `simser:"const=\"RIFF\""`
+	Version uint16  `simser:"const=0x0102,order=be"`
+	Tag     [4]byte `simser:"const=\"WAVE\",order=be"`
+	Size    uint32
+}
+
+// Lengths, limited by max tags, or not limited
+type Limited struct {
+	N     int16
+	Items []uint32 `simser:"len=int(o.N),max=4"`
+	SLen  uint16
+	S     string `simser:"len=int(o.SLen),max=5"`
+	M     uint32
+	Big   []uint16 `simser:"len=int(o.M)"`
+}
+
+// Varints of different sizes, a varint length and slice of varints
+type Varints struct {
+	U8    uint8   `simser:"varint"`
+	I16   int16   `simser:"zigzag"`
+	N     uint32  `simser:"varint"`
+	Items []int64 `simser:"len=int(o.N),zigzag"`
+	S     string  `simser:"prefix=varint"`
+}
+
+// Platform-sized integers, serialized as fixed-size types
+type WireInts struct {
+	I  int     `simser:"as=i16"`
+	U  uint    `simser:"as=u8"`
+	P  uintptr `simser:"as=u32"`
+	L  int     `simser:"as=u64"`
+	Is []int   `simser:"len=2,as=i8"`
+}
+
+// Conditional field, depending on auto-linked length field
+type Mix struct {
+	N     uint8
+	Names []uint16 `simser:"len=int(o.N)"`
+	S     string   `simser:"prefix=varint,if=o.N > 0"`
+}
+
+// Bit fields in containers of explicit size, which they would otherwise close earlier
+type Packed struct {
+	A uint8  `simser:"bits=4/16,order=le,bitorder=lsb"`
+	B uint8  `simser:"bits=4"`
+	C uint8  `simser:"bits=8"`
+	D uint8  `simser:"bits=8/32,order=be"`
+	E uint16 `simser:"bits=16"`
+	F uint8  `simser:"bits=8"`
+}
+
+// Codec field, whose read function doesn't report the number of bytes it needs
+type Plain struct {
+	V uint16 `simser:"codec=plain"`
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/amanofbits/simser/pkg/simser"
+)
+
+func TestVarints(t *testing.T) {
+	testRoundTrips(t, []roundTrip{
+		{
+			name: "zero",
+			in:   &Varints{},
+			data: []byte{0, 0, 0, 0},
+			out:  &Varints{},
+			want: &Varints{Items: []int64{}},
+		},
+		{
+			name: "small",
+			in:   &Varints{U8: 1, I16: -1, Items: []int64{1, -2}, S: "a"},
+			data: []byte{0x01, 0x01, 0x02, 0x02, 0x03, 0x01, 'a'},
+			out:  &Varints{},
+			want: &Varints{U8: 1, I16: -1, N: 2, Items: []int64{1, -2}, S: "a"},
+		},
+		{
+			name: "limits",
+			in:   &Varints{U8: math.MaxUint8, I16: math.MinInt16, Items: []int64{math.MaxInt64, math.MinInt64}},
+			data: []byte{
+				0xFF, 0x01, // U8
+				0xFF, 0xFF, 0x03, // I16
+				0x02,                                                       // N
+				0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01, // Items[0]
+				0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01, // Items[1]
+				0x00, // S
+			},
+			out:  &Varints{},
+			want: &Varints{U8: math.MaxUint8, I16: math.MinInt16, N: 2, Items: []int64{math.MaxInt64, math.MinInt64}, S: ""},
+		},
+	})
+
+	tests := []struct {
+		name  string
+		data  []byte
+		field string
+	}{
+		{name: "uint8", data: []byte{0x80, 0x02}, field: "U8"},
+		{name: "int16", data: []byte{0x00, 0x80, 0x80, 0x04}, field: "I16"},
+		{name: "int16 negative", data: []byte{0x00, 0x81, 0x80, 0x04}, field: "I16"},
+		{name: "too long", data: []byte{0x00, 0x00, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01}, field: "N"},
+		{name: "element", data: []byte{0x00, 0x00, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x02}, field: "Items"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, r := range readers {
+				err := r.read(&Varints{}, tt.data)
+				var fErr *simser.FieldError
+				if !errors.Is(err, simser.ErrVarintOverflow) || !errors.As(err, &fErr) || fErr.Field != tt.field {
+					t.Fatalf("%s() error = %v, want %s: %v", r.name, err, tt.field, simser.ErrVarintOverflow)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWireInts(t *testing.T) {
+	testRoundTrips(t, []roundTrip{
+		{
+			name: "zero",
+			in:   &WireInts{Is: []int{0, 0}},
+			data: make([]byte, 17),
+			out:  &WireInts{},
+		},
+		{
+			name: "limits",
+			in:   &WireInts{I: math.MinInt16, U: math.MaxUint8, P: math.MaxUint32, L: math.MaxInt, Is: []int{math.MinInt8, math.MaxInt8}},
+			data: []byte{
+				0x00, 0x80, // I
+				0xFF,                   // U
+				0xFF, 0xFF, 0xFF, 0xFF, // P
+				0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F, // L
+				0x80, 0x7F, // Is
+			},
+			out: &WireInts{},
+		},
+	})
+
+	testWriteErrors(t, []writeError{
+		{
+			name: "signed below",
+			in:   &WireInts{I: math.MinInt16 - 1, Is: []int{0, 0}},
+			err:  "I: value -32769 overflows int16",
+		},
+		{
+			name: "signed above",
+			in:   &WireInts{I: math.MaxInt16 + 1, Is: []int{0, 0}},
+			err:  "I: value 32768 overflows int16",
+		},
+		{
+			name: "unsigned",
+			in:   &WireInts{U: math.MaxUint8 + 1, Is: []int{0, 0}},
+			err:  "U: value 256 overflows uint8",
+		},
+		{
+			name: "uintptr",
+			in:   &WireInts{P: math.MaxUint32 + 1, Is: []int{0, 0}},
+			err:  "P: value 4294967296 overflows uint32",
+		},
+		{
+			name: "negative as unsigned",
+			in:   &WireInts{L: -1, Is: []int{0, 0}},
+			err:  "L: value -1 overflows uint64",
+		},
+		{
+			name: "element",
+			in:   &WireInts{Is: []int{0, math.MaxInt8 + 1}},
+			err:  "Is[i]: value 128 overflows int8",
+		},
+	})
+}
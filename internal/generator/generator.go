@@ -15,109 +15,566 @@
 package generator
 
 import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/printer"
+	"go/token"
+	"slices"
+	"strings"
+
 	"github.com/amanofbits/simser/internal/domain"
+	"golang.org/x/tools/go/ast/astutil"
 )
 
-func GenStructCode(s domain.InputStruct, out *Output, readFnName, writeFnName string) error {
+// Code generation options, common for all structs in the output.
+type Options struct {
+	ReadFnName  string // Name of deserializing function. It is not generated if empty.
+	WriteFnName string // Name of serializing function. It is not generated if empty.
+	ByteOrder   domain.ByteOrder
+	// Generate io.ReaderFrom, io.WriterTo, encoding.BinaryUnmarshaler and encoding.BinaryMarshaler
+	// implementations, for generated read and write functions.
+	StdInterfaces bool
+	// Generate DecodeFrom, MarshalTo and AppendBinary methods, working with byte slices.
+	ByteSliceMethods bool
+	// Max size of a single slice or string, in bytes, that deserializing functions allocate. 0 means no limit.
+	MaxAlloc int
+}
+
+// Import path of the package with types, used by generated code.
+const RuntimePkgPath = "github.com/amanofbits/simser/pkg/simser"
+
+// Names of methods, generated with Options.ByteSliceMethods.
+// Custom function names must not collide with them.
+var ByteSliceMethods = []string{"DecodeFrom", "MarshalTo", "AppendBinary"}
+
+// Name of the size method, generated for variable-size structs.
+const EncodedSizeMethod = "EncodedSize"
+
+func GenStructCode(s domain.InputStruct, out *Output, opts Options) error {
 	if s.FieldCount() == 0 {
 		return nil
 	}
 
+	out.addStruct(s)
 	sizeGroups := getFieldSizeGroups(s)
 
-	// Generate func (o 'typename')LoadFrom(io.Reader) (*'typename', error)
-	{
-		out.AppendImport("io")
+	// Unused imports are removed from output
+	if usesType(s, isFloat) || usesType(s, isWireInt) {
+		out.AppendImport("math")
+	}
+	if hasConstFields(s) || usesType(s, isVarint) {
+		out.AppendImport(RuntimePkgPath)
+	}
+	if usesType(s, isString) || usesType(s, isBool) || hasLenFields(s) || usesType(s, isBitGroup) || usesType(s, isWireInt) || usesType(s, isCodec) {
+		out.AppendImport("bytes")
+		out.AppendImport("fmt")
+		out.AppendImport("strings")
+	}
 
-		out.AppendF("func (o *%s) %s(r io.Reader) (n int, err error) {\n", s.Name(), readFnName)
-		out.AppendF("p, nRead := 0, 0\n")
+	genEncodedSize(s, out, sizeGroups)
+	out.LF()
 
-		out.AppendF("toRead := ")
-		if domain.IsFixedSize(sizeGroups[0]) {
-			out.AppendF("%d\n", sizeGroups[0])
-		} else {
-			out.AppendF("%s\n", s.Field(0).Type().SizeExpr())
+	if opts.ReadFnName != "" {
+		if err := genReadFn(s, out, sizeGroups, opts, srcReader); err != nil {
+			return err
+		}
+		out.LF()
+		if opts.ByteSliceMethods {
+			if err := genReadFn(s, out, sizeGroups, opts, srcSlice); err != nil {
+				return err
+			}
+			out.LF()
+		}
+		if opts.StdInterfaces {
+			genStdReadMethods(s, out, opts)
+			out.LF()
+		}
+	}
+	if opts.WriteFnName != "" {
+		if err := genWriteFn(s, out, opts); err != nil {
+			return err
+		}
+		if opts.StdInterfaces {
+			out.LF()
+			genStdWriteMethods(s, out, opts)
+		}
+	}
+	return nil
+}
+
+// Generates const 'typename'EncodedSize for fixed-size struct,
+// or func (o 'typename') EncodedSize() int for variable-size one.
+func genEncodedSize(s domain.InputStruct, out *Output, sizeGroups map[int]int) {
+	if isFixedSizeStruct(s) {
+		out.AppendF("// %sEncodedSize is the size of serialized %s, in bytes.\n", s.Name(), s.Name())
+		out.AppendF("const %sEncodedSize = %s\n", s.Name(), encodedSizeExpr(s, sizeGroups))
+		return
+	}
+	out.AppendF("// %s returns the size of serialized o, in bytes.\n", EncodedSizeMethod)
+	out.AppendF("func (o *%s) %s() int {\n", s.Name(), EncodedSizeMethod)
+	if !hasCondFields(s) {
+		out.AppendF("return %s\n", encodedSizeExpr(s, sizeGroups))
+		out.Append("}\n")
+		return
+	}
+	out.AppendF("size := %s\n", encodedSizeExpr(s, sizeGroups))
+	for i := 0; i < s.FieldCount(); i++ {
+		if f := s.Field(i); f.Cond() != "" {
+			// Field comment maps type errors in the condition to the field
+			out.AppendF("// %s\n", f.Name())
+			out.AppendF("if %s {\n", writeCond(s, f))
+			out.AppendF("size += %s\n", writeSizeExpr(f))
+			out.Append("}\n")
+		}
+	}
+	out.Append("return size\n")
+	out.Append("}\n")
+}
+
+// Returns expression, that refers to the size generated by genEncodedSize.
+func encodedSizeRef(s domain.InputStruct) string {
+	if isFixedSizeStruct(s) {
+		return s.Name() + "EncodedSize"
+	}
+	return "o." + EncodedSizeMethod + "()"
+}
+
+func isFixedSizeStruct(s domain.InputStruct) bool {
+	for i := 0; i < s.FieldCount(); i++ {
+		if !domain.IsFixedSize(s.Field(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Generates func (o 'typename') LoadFrom(io.Reader) (n int, err error),
+// or func (o 'typename') DecodeFrom([]byte) (n int, err error) for slice source.
+func genReadFn(s domain.InputStruct, out *Output, sizeGroups map[int]int, opts Options, src readSource) error {
+	out.AppendImport("io")
+
+	// Fields that read data by themselves don't use the buffer position.
+	// With byte slice source, the buffer is used only by fields that read their values from it.
+	// Varints don't count read bytes separately.
+	usesPos, usesBuf, onlyVarints, hasCodecs := false, false, true, false
+	for i := 0; i < s.FieldCount(); i++ {
+		f := s.Field(i)
+		usesPos = usesPos || !readsByItself(f)
+		usesBuf = usesBuf || readsBuffer(f)
+		onlyVarints = onlyVarints && readsByItself(f) && !isString(f.Type())
+		hasCodecs = hasCodecs || isCodec(f.Type())
+	}
+	vars := []string{}
+	if usesPos {
+		vars = append(vars, "p")
+	}
+	if src == srcSlice {
+		out.AppendF("func (o *%s) DecodeFrom(src []byte) (n int, err error) {\n", s.Name())
+		if usesBuf {
+			out.Append("var b []byte\n")
+		}
+	} else {
+		out.AppendF("func (o *%s) %s(r io.Reader) (n int, err error) {\n", s.Name(), opts.ReadFnName)
+		out.Append("var b []byte\n")
+		if !onlyVarints || hasCodecs {
+			vars = append(vars, "nRead")
 		}
+	}
+	vars = append(vars, "toRead")
+	out.AppendF("%s := 0%s\n", strings.Join(vars, ", "), strings.Repeat(", 0", len(vars)-1))
 
-		for i := 0; i < s.FieldCount(); i++ {
-			if s.Field(i).Type().IsSequence() {
-				out.AppendF("sLen, sElSize := 0, 0\n")
-				break
+	seqVars := ""
+	for i := 0; i < s.FieldCount(); i++ {
+		if t, ok := s.Field(i).Type().(*domain.SliceFieldType); ok && isVarint(t.ElType()) {
+			if seqVars == "" {
+				seqVars = "sLen := 0\n"
 			}
+		} else if s.Field(i).Type().IsSequence() && !domain.IsFixedSize(s.Field(i).Type().Size()) {
+			seqVars = "sLen, sElSize := 0, 0\n"
 		}
+	}
+	if seqVars != "" {
+		out.Append(seqVars)
+		// Overflow guard of slice length
+		out.AppendImport("math")
+	}
+	out.AppendImport(RuntimePkgPath)
+	out.Append(tpl_WrapFieldError(s.Name()))
+	out.LF()
 
-		out.LF()
-		out.AppendF("b := make([]byte, toRead)\n")
-		out.Append(tpl_ReadBytesIntoBuf("b")).LF()
-		out.LF()
+	lenChecks := getLenChecks(s)
 
-		for i := 0; i < s.FieldCount(); i++ {
-			field := s.Field(i)
-			out.AppendF("\n// %s\n", field.Name())
-			if i != 0 {
-				if size, ok := sizeGroups[i]; ok {
-					if !domain.IsFixedSize(size) {
-						if field.Type().IsSequence() {
-							seqType := field.Type().(domain.SequenceFieldType)
-							out.AppendF("sLen, sElSize = %s, %s\n", seqType.LenExpr(), seqType.ElType().SizeExpr())
-						}
-						out.Append("p, toRead = 0, sLen * sElSize\n")
-					} else {
-						out.AppendF("p, toRead = 0, %d\n", size)
-					}
-					out.AppendF("if toRead > cap(b) {\n")
-					out.AppendF("b = make([]byte, toRead)\n")
-					out.Append("}\n")
-					out.Append(tpl_ReadBytesIntoBuf("b")).LF()
-				}
+	for i := 0; i < s.FieldCount(); i++ {
+		field := s.Field(i)
+		out.AppendF("\n// %s\n", field.Name())
+		if field.Cond() != "" {
+			out.AppendF("if %s {\n", field.Cond())
+		}
+		size, groupStart := sizeGroups[i]
+		out.Append(tpl_SetErrField(field, groupStart))
+		if groupStart {
+			bufName := "b"
+			if src == srcSlice && !groupReadsBuffer(s, sizeGroups, i) {
+				// Bytes are only skipped
+				bufName = ""
 			}
-			s, err := tpl_ReadField(field, "b")
-			if err != nil {
-				return err
+			out.Append(tpl_ReadGroup(field, size, bufName, src, opts.MaxAlloc))
+		}
+		code, err := tpl_ReadField(field, "b", opts.ByteOrder, src, opts.MaxAlloc)
+		if err != nil {
+			return err
+		}
+		out.AppendF("%s\n", code)
+		if field.Const() != nil {
+			out.AppendF("%s\n", tpl_CheckConst(s.Name(), field))
+		}
+		if field.Cond() != "" {
+			out.Append("}")
+			if field.Skip()&domain.SkipRead == 0 && !field.IsPadding() {
+				out.AppendF(" else {\no.%s = *new(%s)\n}", field.Name(), field.Type().Name())
 			}
-			out.AppendF("%s\n", s)
+			out.LF()
+		}
+		for _, lenField := range lenChecks[i] {
+			out.AppendF("%s\n", tpl_CheckLen(lenField, lenCheckCond(s, lenField)))
 		}
+	}
 
-		out.LF()
-		out.Append("return n, err")
+	out.LF()
+	out.Append("return n, err")
+	out.Append("}\n")
+	return nil
+}
+
+// Generates func (o 'typename') SaveTo(io.Writer) (n int, err error).
+// With byte slice methods, generates func (o 'typename') MarshalTo([]byte) (n int, err error) instead,
+// and SaveTo and AppendBinary wrapping it. AppendBinary returns an error, like encoding.BinaryAppender.
+func genWriteFn(s domain.InputStruct, out *Output, opts Options) error {
+	out.AppendImport("io")
+
+	sizeExpr := encodedSizeRef(s)
+
+	// Size of variable-size struct is checked after writing, so that codecs and varints are not encoded twice.
+	// Capacity is limited, so that writing past len(dst) allocates instead.
+	checkSizeAfter := opts.ByteSliceMethods && !isFixedSizeStruct(s)
+	if opts.ByteSliceMethods {
+		out.AppendF("func (o *%s) MarshalTo(dst []byte) (n int, err error) {\n", s.Name())
+		if checkSizeAfter {
+			out.Append("b := dst[:0:len(dst)]\n")
+		} else {
+			out.AppendF("if len(dst) < %s {\n", sizeExpr)
+			out.Append("return 0, io.ErrShortBuffer\n")
+			out.Append("}\n")
+			out.Append("b := dst[:0]\n")
+		}
+	} else {
+		out.AppendF("func (o *%s) %s(w io.Writer) (n int, err error) {\n", s.Name(), opts.WriteFnName)
+		out.AppendF("b := make([]byte, 0, %s)\n", sizeExpr)
+	}
+	out.LF()
+
+	for i := 0; i < s.FieldCount(); i++ {
+		field := s.Field(i)
+		out.AppendF("\n// %s", field.Name()).LF()
+		if field.Cond() != "" {
+			out.AppendF("if %s {\n", writeCond(s, field))
+		}
+		code, err := tpl_WriteField(field, "b", opts.ByteOrder)
+		if err != nil {
+			return err
+		}
+		out.AppendF("%s\n", code)
+		if field.Cond() != "" {
+			out.Append("}\n")
+		}
+	}
+
+	if !opts.ByteSliceMethods {
+		out.Append("return w.Write(b)")
 		out.Append("}\n")
+		return nil
 	}
+	if checkSizeAfter {
+		out.Append("if len(b) > len(dst) {\n")
+		out.Append("return 0, io.ErrShortBuffer\n")
+		out.Append("}\n")
+	}
+	out.Append("return len(b), nil")
+	out.Append("}\n\n")
 
-	out.LF()
+	out.AppendF("func (o *%s) AppendBinary(dst []byte) ([]byte, error) {\n", s.Name())
+	out.AppendF("size := %s\n", sizeExpr)
+	// Grown like with append, so that appending many times doesn't copy dst each time
+	out.Append("if cap(dst)-len(dst) < size {\n")
+	out.Append("dst = append(dst, make([]byte, size)...)[:len(dst)]\n")
+	out.Append("}\n")
+	out.Append("n, err := o.MarshalTo(dst[len(dst) : len(dst)+size])\n")
+	out.Append("if err != nil {\n")
+	out.Append("return dst, err\n")
+	out.Append("}\n")
+	out.Append("return dst[:len(dst)+n], nil\n")
+	out.Append("}\n\n")
 
-	// Gen func (o 'typename') SaveTo(io.Writer) (n int, err error)
-	{
-		out.AppendImport("io")
+	out.AppendF("func (o *%s) %s(w io.Writer) (n int, err error) {\n", s.Name(), opts.WriteFnName)
+	out.AppendF("b := make([]byte, %s)\n", sizeExpr)
+	out.Append("if n, err = o.MarshalTo(b); err != nil {\n")
+	out.Append("return 0, err\n")
+	out.Append("}\n")
+	out.Append("return w.Write(b[:n])\n")
+	out.Append("}\n")
+	return nil
+}
+
+// Expression of the struct size, when it is written. Conditional fields are not included.
+func encodedSizeExpr(s domain.InputStruct, sizeGroups map[int]int) string {
+	sb := fstringBuilder{}
+	constSize := 0
+	for i := 0; i < s.FieldCount(); i++ {
+		size, ok := sizeGroups[i]
+		if !ok || s.Field(i).Cond() != "" {
+			continue
+		}
+		if !domain.IsFixedSize(size) {
+			expr := writeSizeExpr(s.Field(i))
+			sb.WriteFString(" + %s", domain.ParenthesizeIntExpr(expr))
+			continue
+		}
+		constSize += size
+	}
+	return fmt.Sprintf("%d%s", constSize, sb.String())
+}
+
+// Size of variable-size field, when it is written.
+// Writer uses actual length of slices and strings, not length expressions, which may be stale.
+func writeSizeExpr(f domain.StructField) string {
+	if f.Skip()&domain.SkipWrite != 0 {
+		return f.Type().SizeExpr()
+	}
+	if vt, ok := f.Type().(*domain.VarintFieldType); ok && f.LenOf() != "" {
+		return vt.SizeOfExpr(fmt.Sprintf("len(o.%s)", f.LenOf()))
+	}
+	switch t := f.Type().(type) {
+	case *domain.SliceFieldType:
+		if el, ok := t.ElType().(*domain.VarintFieldType); ok {
+			return el.SliceSizeOfExpr("o." + f.Name())
+		}
+		return fmt.Sprintf("%s * len(o.%s)", domain.ParenthesizeIntExpr(t.ElType().SizeExpr()), f.Name())
+	case *domain.StringFieldType:
+		if t.Encoding() == domain.StringLenExpr {
+			return fmt.Sprintf("len(o.%s)", f.Name())
+		}
+	}
+	return f.Type().SizeExpr()
+}
+
+func hasConstFields(s domain.InputStruct) bool {
+	for i := 0; i < s.FieldCount(); i++ {
+		if s.Field(i).Const() != nil {
+			return true
+		}
+	}
+	return false
+}
 
-		out.AppendF("func (o *%s) %s(w io.Writer) (n int, err error) {\n", s.Name(), writeFnName)
+func hasCondFields(s domain.InputStruct) bool {
+	for i := 0; i < s.FieldCount(); i++ {
+		if s.Field(i).Cond() != "" {
+			return true
+		}
+	}
+	return false
+}
 
-		out.AppendF("b := make([]byte, 0, ")
-		sb := fstringBuilder{}
-		constSize := 0
-		for i, size := range sizeGroups {
-			if !domain.IsFixedSize(size) {
-				expr := s.Field(i).Type().SizeExpr()
-				sb.WriteFString("+ %s", domain.ParenthesizeIntExpr(expr))
+// Returns condition of the field, as evaluated by writer.
+// Length fields are written as actual lengths of the fields they refer to, so their values
+// in the condition are replaced with these lengths, to match what reader gets.
+func writeCond(s domain.InputStruct, f domain.StructField) string {
+	lenFields := map[string]domain.StructField{}
+	for i := 0; i < s.FieldCount(); i++ {
+		if lf := s.Field(i); lf.LenOf() != "" && lf.Skip()&domain.SkipWrite == 0 {
+			lenFields[lf.Name()] = lf
+		}
+	}
+	e, err := goparser.ParseExpr(f.Cond())
+	if len(lenFields) == 0 || err != nil {
+		return f.Cond() // Invalid expressions are reported by type check
+	}
+	e = astutil.Apply(e, func(c *astutil.Cursor) bool {
+		sel, ok := c.Node().(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		lf, isLen := lenFields[sel.Sel.Name]
+		if obj, ok := sel.X.(*ast.Ident); !isLen || !ok || obj.Name != "o" {
+			return true
+		}
+		c.Replace(&ast.CallExpr{
+			Fun: ast.NewIdent(lf.Type().Name()),
+			Args: []ast.Expr{&ast.CallExpr{
+				Fun:  ast.NewIdent("len"),
+				Args: []ast.Expr{&ast.SelectorExpr{X: ast.NewIdent("o"), Sel: ast.NewIdent(lf.LenOf())}},
+			}},
+		})
+		return false
+	}, nil).(ast.Expr)
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), e); err != nil {
+		return f.Cond()
+	}
+	return buf.String()
+}
+
+// Returns condition, under which both length field and the field it refers to are read,
+// or empty string if they are unconditional.
+func lenCheckCond(s domain.InputStruct, lenField domain.StructField) string {
+	conds := []string{}
+	for i := 0; i < s.FieldCount(); i++ {
+		f := s.Field(i)
+		cond := "(" + f.Cond() + ")"
+		if (f.Name() == lenField.Name() || f.Name() == lenField.LenOf()) && f.Cond() != "" && !slices.Contains(conds, cond) {
+			conds = append(conds, cond)
+		}
+	}
+	return strings.Join(conds, " && ")
+}
+
+func hasLenFields(s domain.InputStruct) bool {
+	for i := 0; i < s.FieldCount(); i++ {
+		if s.Field(i).LenOf() != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns length fields, whose values must be checked after reading the field with given index.
+// Length is checked when both length field and the field it refers to are read.
+func getLenChecks(s domain.InputStruct) map[int][]domain.StructField {
+	checks := map[int][]domain.StructField{}
+	for i := 0; i < s.FieldCount(); i++ {
+		lenField := s.Field(i)
+		if lenField.LenOf() == "" || lenField.Skip()&domain.SkipRead != 0 {
+			continue
+		}
+		for j := 0; j < s.FieldCount(); j++ {
+			if s.Field(j).Name() != lenField.LenOf() || s.Field(j).Skip()&domain.SkipRead != 0 {
 				continue
 			}
-			constSize += size
+			at := i
+			if j > at {
+				at = j
+			}
+			checks[at] = append(checks[at], lenField)
 		}
-		out.AppendF("%d %s)\n", constSize, sb.String())
-		out.LF()
+	}
+	return checks
+}
 
-		for i := 0; i < s.FieldCount(); i++ {
-			field := s.Field(i)
-			out.AppendF("\n// %s", field.Name()).LF()
-			s, err := tpl_WriteField(field, "b")
-			if err != nil {
-				return err
+// Returns true if pred is true for type of any field of the struct, including nested ones.
+func usesType(s domain.InputStruct, pred func(domain.FieldType) bool) bool {
+	for i := 0; i < s.FieldCount(); i++ {
+		if containsType(s.Field(i).Type(), pred) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsType(t domain.FieldType, pred func(domain.FieldType) bool) bool {
+	if pred(t) {
+		return true
+	}
+	switch typ := t.(type) {
+	case domain.SequenceFieldType:
+		return containsType(typ.ElType(), pred)
+	case *domain.StructFieldType:
+		for i := 0; i < typ.FieldCount(); i++ {
+			if containsType(typ.Field(i).Type(), pred) {
+				return true
 			}
-			out.AppendF("%s\n", s)
 		}
+	case *domain.BitGroupFieldType:
+		for i := 0; i < typ.FieldCount(); i++ {
+			if pred(typ.Field(i).Type()) {
+				return true
+			}
+		}
+	}
+	return false
+}
 
-		out.Append("return w.Write(b)")
-		out.Append("}\n")
+func isFloat(t domain.FieldType) bool {
+	st, ok := t.(*domain.SimpleFieldType)
+	return ok && st.IsFloat()
+}
+
+func isBool(t domain.FieldType) bool {
+	_, ok := t.(*domain.BoolFieldType)
+	return ok
+}
+
+// Returns true for varints, and strings with varint length prefix.
+func isVarint(t domain.FieldType) bool {
+	if st, ok := t.(*domain.StringFieldType); ok && st.Encoding() == domain.StringPrefixed {
+		t = st.Prefix()
 	}
+	_, ok := t.(*domain.VarintFieldType)
+	return ok
+}
 
-	return nil
+// Returns true if the field reads data from the source by itself, and doesn't use the buffer position.
+func readsByItself(f domain.StructField) bool {
+	switch t := f.Type().(type) {
+	case *domain.VarintFieldType, *domain.CodecFieldType:
+		return true
+	case *domain.SliceFieldType:
+		return isVarint(t.ElType())
+	case *domain.StringFieldType:
+		return t.Encoding() == domain.StringNulTerminated
+	}
+	return false
+}
+
+// Returns true if the field reads its value from the buffer.
+func readsBuffer(f domain.StructField) bool {
+	if f.Skip()&domain.SkipRead != 0 {
+		return false
+	}
+	return !readsByItself(f) || isString(f.Type())
+}
+
+// Returns true if any field of the size group, starting at index start, reads its value from the buffer.
+func groupReadsBuffer(s domain.InputStruct, sizeGroups map[int]int, start int) bool {
+	for i := start; i < s.FieldCount(); i++ {
+		if _, groupStart := sizeGroups[i]; groupStart && i != start {
+			break
+		}
+		if readsBuffer(s.Field(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isCodec(t domain.FieldType) bool {
+	_, ok := t.(*domain.CodecFieldType)
+	return ok
+}
+
+func isWireInt(t domain.FieldType) bool {
+	_, ok := t.(*domain.WireIntFieldType)
+	return ok
+}
+
+func isBitGroup(t domain.FieldType) bool {
+	_, ok := t.(*domain.BitGroupFieldType)
+	return ok
+}
+
+func isString(t domain.FieldType) bool {
+	_, ok := t.(*domain.StringFieldType)
+	return ok
 }
@@ -0,0 +1,137 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"bytes"
+	"go/ast"
+	"path/filepath"
+	"testing"
+
+	"github.com/amanofbits/simser/internal/domain"
+	"github.com/amanofbits/simser/internal/parser"
+)
+
+// Accepts all types
+type acceptAll struct{}
+
+func (acceptAll) Accepts(*ast.TypeSpec) bool { return true }
+func (acceptAll) IsDrained() bool            { return false }
+func (acceptAll) AcceptsAll() bool           { return true }
+func (acceptAll) String() string             { return "all" }
+
+// Generates code for all structs of the package in dir, as a single file.
+func genPackage(t *testing.T, dir string, opts Options) []byte {
+	t.Helper()
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := parser.ParsePackage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileStructs, err := pkg.GetInputStructs(acceptAll{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	structs := []domain.InputStruct{}
+	for _, fs := range fileStructs {
+		structs = append(structs, fs.Structs...)
+	}
+	if len(structs) < 2 {
+		t.Fatalf("found %d structs in %s, want several", len(structs), dir)
+	}
+
+	out := NewOutput(pkg.Pkg, filepath.Join(dir, "simser_gen.go"), []string{"-types=all"})
+	for _, s := range structs {
+		if err := GenStructCode(s, out, opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf := bytes.Buffer{}
+	if _, err := out.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOutputIsReproducible(t *testing.T) {
+	opts := Options{
+		ReadFnName:       "LoadFrom",
+		WriteFnName:      "SaveTo",
+		ByteOrder:        domain.LittleEndian,
+		StdInterfaces:    true,
+		ByteSliceMethods: true,
+	}
+	first := genPackage(t, "../e2e", opts)
+	for _, imp := range []string{"bytes", "fmt", "io", "math", RuntimePkgPath} {
+		if !bytes.Contains(first, []byte(`"`+imp+`"`)) {
+			t.Fatalf("generated code doesn't import %s:\n%s", imp, first)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if next := genPackage(t, "../e2e", opts); !bytes.Equal(next, first) {
+			t.Fatalf("generated code differs between runs:\n%s\n\nand\n\n%s", first, next)
+		}
+	}
+}
+
+func TestSingleFunction(t *testing.T) {
+	out := genPackage(t, "../e2e", Options{ReadFnName: "Parse", ByteOrder: domain.LittleEndian})
+	if !bytes.Contains(out, []byte(") Parse(r io.Reader) (n int, err error) {")) {
+		t.Errorf("generated code has no Parse function:\n%s", out)
+	}
+	if bytes.Contains(out, []byte("SaveTo")) || bytes.Contains(out, []byte("(w io.Writer)")) {
+		t.Errorf("generated code has serializing function:\n%s", out)
+	}
+
+	out = genPackage(t, "../e2e", Options{WriteFnName: "Save", ByteOrder: domain.LittleEndian})
+	if !bytes.Contains(out, []byte(") Save(w io.Writer) (n int, err error) {")) {
+		t.Errorf("generated code has no Save function:\n%s", out)
+	}
+	if bytes.Contains(out, []byte("(r io.Reader)")) {
+		t.Errorf("generated code has deserializing function:\n%s", out)
+	}
+}
+
+func TestMaxAlloc(t *testing.T) {
+	out := genPackage(t, "../e2e", Options{ReadFnName: "LoadFrom", ByteOrder: domain.LittleEndian, MaxAlloc: 64})
+	// Limits of slices are in elements, and the lower of max tag and -max-alloc applies
+	for _, want := range []string{
+		"&simser.LengthError{Length: sLen, Max: 32}",  // Limited.Big, []uint16
+		"&simser.LengthError{Length: sLen, Max: 4}",   // Limited.Items, max=4
+		"&simser.LengthError{Length: toRead, Max: 5}", // Limited.S, max=5
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("generated code doesn't contain %s", want)
+		}
+	}
+}
+
+func TestReadErrPrefix(t *testing.T) {
+	tests := []struct{ expr, want string }{
+		{"o.Flag", ""},
+		{"o.Flags[i]", ""},
+		{"o.Header.Flag", "Flag: "},
+		{"o.Items[i].Flag", "Flag: "},
+		{"o.Items[i].Inner.Flags[j]", "Inner.Flags: "},
+	}
+	for _, tt := range tests {
+		if got := readErrPrefix(tt.expr); got != tt.want {
+			t.Errorf("readErrPrefix(%s) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
@@ -15,37 +15,51 @@
 package generator
 
 import (
+	"bytes"
 	"fmt"
+	"go/ast"
+	"go/format"
 	"go/parser"
-	"go/printer"
 	"io"
-	"os"
-	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/amanofbits/simser/internal/domain"
+	"golang.org/x/exp/maps"
+	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/go/packages"
 )
 
 type Output struct {
-	pkg     *packages.Package
-	header  fstringBuilder
-	imports map[string]string
-	code    fstringBuilder
+	pkg      *packages.Package
+	filename string               // File the output is written to
+	structs  []domain.InputStruct // Structs the code is generated for
+	header   fstringBuilder
+	imports  map[string]string
+	code     fstringBuilder
 }
 
-func NewOutput(pkg *packages.Package) (o *Output) {
+// Creates output for package, that is written to filename.
+// cmdArgs are command line arguments, mentioned in the header.
+func NewOutput(pkg *packages.Package, filename string, cmdArgs []string) (o *Output) {
 	o = &Output{
-		pkg:     pkg,
-		header:  fstringBuilder{},
-		imports: map[string]string{},
-		code:    fstringBuilder{},
+		pkg:      pkg,
+		filename: filename,
+		header:   fstringBuilder{},
+		imports:  map[string]string{},
+		code:     fstringBuilder{},
 	}
-	o.header.WriteFString("// Code generated by \"%s %s\"; DO NOT EDIT.\n\n", filepath.Base(os.Args[0]), strings.Join(os.Args[1:], " "))
+	// Program name is fixed, as os.Args[0] depends on how simser is run
+	o.header.WriteFString("// Code generated by \"%s\"; DO NOT EDIT.\n\n", strings.Join(append([]string{"simser"}, cmdArgs...), " "))
 	o.header.WriteFString("package %s\n", pkg.Name)
 
 	return o
 }
 
+func (o *Output) addStruct(s domain.InputStruct) {
+	o.structs = append(o.structs, s)
+}
+
 func (o *Output) AppendImport(imp string) {
 	imp = strings.Trim(imp, "\"")
 	o.imports[imp] = ""
@@ -75,9 +89,11 @@ func (o Output) WriteTo(w io.Writer) (n int64, err error) {
 
 	src.WriteString(o.header.String())
 
+	imports := maps.Keys(o.imports)
+	slices.Sort(imports)
 	src.WriteString("import (\n")
-	for imp, name := range o.imports {
-		src.WriteFString("%s \"%s\"\n", name, imp)
+	for _, imp := range imports {
+		src.WriteFString("%s \"%s\"\n", o.imports[imp], imp)
 	}
 	src.WriteString(")\n")
 
@@ -85,16 +101,43 @@ func (o Output) WriteTo(w io.Writer) (n int64, err error) {
 
 	srcStr := src.String()
 
-	// Parse src to check for errors
-	f, err := parser.ParseFile(o.pkg.Fset, "", srcStr, parser.ParseComments)
+	// Parse src to check for errors. Package file set is used for type checking with package files
+	fset := o.pkg.Fset
+	f, err := parser.ParseFile(fset, o.filename, srcStr, parser.ParseComments)
 	if err != nil {
 		return 0, err
 	}
 
-	// Format src and print to a writer
-	if err := printer.Fprint(w, o.pkg.Fset, f); err != nil {
+	// Imports are appended per struct, not per generated code, so some of them may be unused
+	unused := []*ast.ImportSpec{}
+	for _, imp := range f.Imports {
+		if !astutil.UsesImport(f, strings.Trim(imp.Path.Value, "\"")) {
+			unused = append(unused, imp)
+		}
+	}
+	for _, imp := range unused {
+		name := ""
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		astutil.DeleteNamedImport(fset, f, name, strings.Trim(imp.Path.Value, "\""))
+	}
+	ast.SortImports(fset, f)
+
+	if err := o.typeCheck(f); err != nil {
+		return 0, err
+	}
+
+	// Format the same way gofmt does. Source is formatted once more, as import deletion can leave blank lines
+	buf := bytes.Buffer{}
+	if err := format.Node(&buf, fset, f); err != nil {
+		return 0, fmt.Errorf("error formatting code, %w", err)
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
 		return 0, fmt.Errorf("error formatting code, %w", err)
 	}
 
-	return 0, nil
+	written, err := w.Write(code)
+	return int64(written), err
 }
@@ -0,0 +1,93 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"github.com/amanofbits/simser/internal/domain"
+)
+
+// Names of methods of standard interfaces, generated with Options.StdInterfaces.
+// Custom function names must not collide with them.
+var StdInterfaceMethods = []string{"ReadFrom", "WriteTo", "MarshalBinary", "UnmarshalBinary"}
+
+// Generates io.ReaderFrom and encoding.BinaryUnmarshaler implementations, wrapping read function.
+// With byte slice methods, UnmarshalBinary wraps DecodeFrom instead.
+func genStdReadMethods(s domain.InputStruct, out *Output, opts Options) {
+	out.AppendImport("encoding")
+	out.AppendImport("fmt")
+	out.AppendImport("io")
+
+	out.AppendF("func (o *%s) ReadFrom(r io.Reader) (int64, error) {\n", s.Name())
+	out.AppendF("n, err := o.%s(r)\n", opts.ReadFnName)
+	out.Append("return int64(n), err\n")
+	out.Append("}\n\n")
+
+	out.AppendF("func (o *%s) UnmarshalBinary(data []byte) error {\n", s.Name())
+	if opts.ByteSliceMethods {
+		out.Append("n, err := o.DecodeFrom(data)\n")
+		out.Append("if err != nil {\n")
+		out.Append("return err\n")
+		out.Append("}\n")
+		out.Append("if n != len(data) {\n")
+		out.AppendF("return fmt.Errorf(\"%s: %%d bytes left after unmarshaling\", len(data)-n)\n", s.Name())
+		out.Append("}\n")
+	} else {
+		out.AppendImport("bytes")
+		out.Append("r := bytes.NewReader(data)\n")
+		out.AppendF("if _, err := o.%s(r); err != nil {\n", opts.ReadFnName)
+		out.Append("return err\n")
+		out.Append("}\n")
+		out.Append("if r.Len() != 0 {\n")
+		out.AppendF("return fmt.Errorf(\"%s: %%d bytes left after unmarshaling\", r.Len())\n", s.Name())
+		out.Append("}\n")
+	}
+	out.Append("return nil\n")
+	out.Append("}\n\n")
+
+	out.Append("var (\n")
+	out.AppendF("_ io.ReaderFrom = (*%s)(nil)\n", s.Name())
+	out.AppendF("_ encoding.BinaryUnmarshaler = (*%s)(nil)\n", s.Name())
+	out.Append(")\n")
+}
+
+// Generates io.WriterTo and encoding.BinaryMarshaler implementations, wrapping write function.
+// With byte slice methods, MarshalBinary wraps AppendBinary instead.
+func genStdWriteMethods(s domain.InputStruct, out *Output, opts Options) {
+	out.AppendImport("encoding")
+	out.AppendImport("io")
+
+	out.AppendF("func (o *%s) WriteTo(w io.Writer) (int64, error) {\n", s.Name())
+	out.AppendF("n, err := o.%s(w)\n", opts.WriteFnName)
+	out.Append("return int64(n), err\n")
+	out.Append("}\n\n")
+
+	out.AppendF("func (o *%s) MarshalBinary() ([]byte, error) {\n", s.Name())
+	if opts.ByteSliceMethods {
+		out.Append("return o.AppendBinary(nil)\n")
+	} else {
+		out.AppendImport("bytes")
+		out.Append("var buf bytes.Buffer\n")
+		out.AppendF("if _, err := o.%s(&buf); err != nil {\n", opts.WriteFnName)
+		out.Append("return nil, err\n")
+		out.Append("}\n")
+		out.Append("return buf.Bytes(), nil\n")
+	}
+	out.Append("}\n\n")
+
+	out.Append("var (\n")
+	out.AppendF("_ io.WriterTo = (*%s)(nil)\n", s.Name())
+	out.AppendF("_ encoding.BinaryMarshaler = (*%s)(nil)\n", s.Name())
+	out.Append(")\n")
+}
@@ -15,8 +15,9 @@
 package generator
 
 import (
-	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/amanofbits/simser/internal/domain"
 )
@@ -31,50 +32,326 @@ if err != nil {
 }`, bufName)
 }
 
-func tpl_WriteField(f domain.StructField, bufName string) (t string, err error) {
+// read toRead bytes, growing the buffer if needed
+func tpl_GrowAndReadBytesIntoBuf(bufName string) string {
+	return fmt.Sprintf(
+		`if toRead > cap(%s) {
+	%s = make([]byte, toRead)
+}
+%s`, bufName, bufName, tpl_ReadBytesIntoBuf(bufName))
+}
+
+// Source of data for generated deserializing functions.
+type readSource uint8
+
+const (
+	srcReader readSource = iota // io.Reader 'r', data is read into the buffer.
+	srcSlice                    // []byte 'src', the buffer is a window into it.
+)
+
+// read toRead bytes of variable size, growing the buffer in chunks, as data arrives
+func tpl_ReadChunkedBytesIntoBuf(bufName string) string {
+	return fmt.Sprintf(
+		`%s, nRead, err = simser.ReadFull(r, %s, toRead)
+n += nRead
+if err != nil {
+	return n, err
+}`, bufName, bufName)
+}
+
+// take toRead bytes from src into the buffer, the buffer points into src.
+// With empty bufName, the bytes are only skipped.
+func tpl_SliceBytesIntoBuf(bufName string) string {
+	take := ""
+	if bufName != "" {
+		take = fmt.Sprintf("%s = src[n : n+toRead]\n", bufName)
+	}
+	return fmt.Sprintf(
+		`if len(src)-n < toRead {
+	return n, io.ErrUnexpectedEOF
+}
+%sn += toRead`, take)
+}
+
+// get toRead bytes into the buffer from the source
+func tpl_TakeBytesIntoBuf(bufName string, src readSource) string {
+	if src == srcSlice {
+		return tpl_SliceBytesIntoBuf(bufName)
+	}
+	return tpl_GrowAndReadBytesIntoBuf(bufName)
+}
+
+// get toRead bytes into the buffer from the source, where toRead comes from the data
+func tpl_TakeVarBytesIntoBuf(bufName string, src readSource) string {
+	if src == srcSlice {
+		return tpl_SliceBytesIntoBuf(bufName)
+	}
+	return tpl_ReadChunkedBytesIntoBuf(bufName)
+}
+
+// Returns max length of a slice with elements of elSize, or of a string with elSize 1, that is allowed on read,
+// by 'max' tag value maxLen and max allocation size maxAlloc. Zero maxLen and maxAlloc mean no limit.
+func readLenLimit(maxLen, elSize, maxAlloc int) (limit int, ok bool) {
+	if maxLen > 0 {
+		limit, ok = maxLen, true
+	}
+	if maxAlloc > 0 && elSize > 0 && (!ok || maxAlloc/elSize < limit) {
+		limit, ok = maxAlloc/elSize, true
+	}
+	return limit, ok
+}
+
+// Fails if length lenVar, read or computed from the data, is negative or exceeds the limit.
+// Without limit, lenVar*elSize must not overflow.
+func tpl_CheckReadLen(lenVar string, maxLen, elSize, maxAlloc int) string {
 	sb := fstringBuilder{}
+	if limit, ok := readLenLimit(maxLen, elSize, maxAlloc); ok {
+		sb.WriteFString("if %s < 0 || %s > %d {\n", lenVar, lenVar, limit)
+		sb.WriteFString("return n, &simser.LengthError{Length: %s, Max: %d}\n", lenVar, limit)
+	} else {
+		if elSize > 1 {
+			sb.WriteFString("if %s < 0 || %s > math.MaxInt/%d {\n", lenVar, lenVar, elSize)
+		} else {
+			sb.WriteFString("if %s < 0 {\n", lenVar)
+		}
+		sb.WriteFString("return n, &simser.LengthError{Length: %s, Max: -1}\n", lenVar)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Reads a group of fields of given size, starting with field f, into the buffer.
+// Fields that read data by themselves produce no code here.
+// Lengths of variable-size fields are checked against their limits, see tpl_CheckReadLen.
+func tpl_ReadGroup(f domain.StructField, size int, bufName string, src readSource, maxAlloc int) string {
+	sb := fstringBuilder{}
+
+	if domain.IsFixedSize(size) {
+		sb.WriteFString("p, toRead = 0, %d\n", size)
+		sb.WriteString(tpl_TakeBytesIntoBuf(bufName, src))
+		sb.WriteString("\n")
+		return sb.String()
+	}
 
 	switch fType := f.Type().(type) {
+	case *domain.VarintFieldType, *domain.CodecFieldType:
+		return ""
+	case *domain.SliceFieldType:
+		if el, ok := fType.ElType().(*domain.VarintFieldType); ok {
+			// Elements are read one by one, but the length is limited by their size in memory
+			sb.WriteFString("sLen = %s\n", fType.LenExpr())
+			sb.WriteString(tpl_CheckReadLen("sLen", fType.MaxLen(), el.IntType().Size(), maxAlloc))
+			return sb.String()
+		}
+		elSize := fType.ElType().Size()
+		sb.WriteFString("sLen, sElSize = %s, %d\n", fType.LenExpr(), elSize)
+		sb.WriteString(tpl_CheckReadLen("sLen", fType.MaxLen(), elSize, maxAlloc))
+		sb.WriteString("p, toRead = 0, sLen * sElSize\n")
+	case domain.SequenceFieldType:
+		sb.WriteFString("sLen, sElSize = %s, %s\n", fType.LenExpr(), fType.ElType().SizeExpr())
+		sb.WriteString("p, toRead = 0, sLen * sElSize\n")
+	case *domain.StringFieldType:
+		if fType.Encoding() != domain.StringLenExpr {
+			return ""
+		}
+		sb.WriteFString("p, toRead = 0, %s\n", fType.LenExpr())
+		sb.WriteString(tpl_CheckReadLen("toRead", fType.MaxLen(), 1, maxAlloc))
+	default:
+		sb.WriteFString("p, toRead = 0, %s\n", fType.SizeExpr())
+	}
+	sb.WriteString(tpl_TakeVarBytesIntoBuf(bufName, src))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func tpl_WriteField(f domain.StructField, bufName string, order domain.ByteOrder) (t string, err error) {
+	sb := fstringBuilder{}
+	err = tpl_WriteStructField(bufName, "o", f, order, 0, &sb)
+	return sb.String(), err
+}
+
+// Appends serialized field f of struct objExpr to the buffer, honoring field's skip mode.
+func tpl_WriteStructField(bufName, objExpr string, f domain.StructField, order domain.ByteOrder, depth int, dst *fstringBuilder) error {
+	if f.Skip()&domain.SkipWrite != 0 {
+		tpl_AppendFillBytes(bufName, f.Type().SizeExpr(), f.FillByte(), depth, dst)
+		return nil
+	}
+	if f.LenOf() != "" {
+		tpl_AppendLenToBytes(bufName, objExpr, f, order, dst)
+		return nil
+	}
+	if c := f.Const(); c != nil {
+		tpl_AppendBytes(bufName, c.Bytes(f.ByteOrder(order)), dst)
+		return nil
+	}
+	if bt, ok := f.Type().(*domain.BitGroupFieldType); ok {
+		tpl_AppendBitGroup(bufName, objExpr, bt, f.ByteOrder(order), dst)
+		return nil
+	}
+	return tpl_WriteValue(bufName, objExpr+"."+f.Name(), f.Type(), f.ByteOrder(order), depth, dst)
+}
+
+// Appends actual length of the field, referred by f.LenOf(), instead of f value.
+func tpl_AppendLenToBytes(bufName, objExpr string, f domain.StructField, order domain.ByteOrder, dst *fstringBuilder) {
+	vt, isVarint := f.Type().(*domain.VarintFieldType)
+	var t *domain.SimpleFieldType
+	switch typ := f.Type().(type) {
+	case *domain.VarintFieldType:
+		t = typ.IntType()
+	case *domain.WireIntFieldType:
+		t = typ.Wire()
+	default:
+		t = f.Type().(*domain.SimpleFieldType)
+	}
+	lenExpr := fmt.Sprintf("len(%s.%s)", objExpr, f.LenOf())
+
+	maxBits := t.BitSize()
+	if t.IsSigned() {
+		maxBits--
+	}
+	if maxBits < 63 {
+		dst.WriteFString("if uint64(%s) > 0x%X {\n", lenExpr, uint64(1)<<maxBits-1)
+		dst.WriteFString("return 0, fmt.Errorf(\"%s: length %%d overflows %s\", %s)\n", f.LenOf(), t.Name(), lenExpr)
+		dst.WriteString("}\n")
+	}
+	if isVarint {
+		tpl_AppendVarint(bufName, lenExpr, vt, dst)
+		return
+	}
+	tpl_AppendSimpleTypeToBytes(bufName, fmt.Sprintf("%s(%s)", t.Name(), lenExpr), t, f.ByteOrder(order), dst)
+}
+
+// Checks that length field f matches the length of the field it refers to.
+// cond is the condition, under which both fields are read, if any.
+func tpl_CheckLen(f domain.StructField, cond string) string {
+	check := fmt.Sprintf("len(o.%s) != int(o.%s)", f.LenOf(), f.Name())
+	if cond != "" {
+		check = fmt.Sprintf("%s && %s", cond, check)
+	}
+	return fmt.Sprintf(`if %s {
+	return n, fmt.Errorf("length %%d of %s does not match %s %%d", len(o.%s), o.%s)
+}`, check, f.LenOf(), f.Name(), f.LenOf(), f.Name())
+}
+
+// Wraps errors, returned by reader, into simser.FieldError with the field being read.
+// Like binary.Read, io.EOF is returned as is only if no bytes were read, and becomes io.ErrUnexpectedEOF otherwise.
+// simser.ConstMismatchError already holds the field, so it is not wrapped.
+func tpl_WrapFieldError(structName string) string {
+	return fmt.Sprintf(`errField, errOffset := "", 0
+defer func() {
+	if err == nil || (err == io.EOF && n == 0) {
+		return
+	}
+	if _, ok := err.(*simser.ConstMismatchError); ok {
+		return
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	err = &simser.FieldError{Type: "%s", Field: errField, Offset: errOffset, Err: err}
+}()
+`, structName)
+}
+
+// Sets the field, reported in reader errors. Offset of the field, which starts a size group,
+// is taken before the group is read.
+func tpl_SetErrField(f domain.StructField, groupStart bool) string {
+	if groupStart {
+		return fmt.Sprintf("errField, errOffset = \"%s\", n\n", f.Name())
+	}
+	return fmt.Sprintf("errField, errOffset = \"%s\", n-toRead+p\n", f.Name())
+}
+
+// Appends constant bytes to the buffer.
+func tpl_AppendBytes(bufName string, data []byte, dst *fstringBuilder) {
+	dst.WriteFString("%s = append(%s", bufName, bufName)
+	for _, b := range data {
+		dst.WriteFString(", 0x%02X", b)
+	}
+	dst.WriteString(")")
+}
+
+// Checks that field f, which was just read, has its constant value.
+func tpl_CheckConst(structName string, f domain.StructField) string {
+	return fmt.Sprintf(`if o.%s != %s {
+	return n, &simser.ConstMismatchError{Type: "%s", Field: "%s", Offset: n - toRead + p - %d, Expected: %s, Actual: o.%s}
+}`, f.Name(), f.Const().Expr(), structName, f.Name(), f.Type().Size(), f.Const().Expr(), f.Name())
+}
+
+// Appends size bytes with value fill to the buffer.
+func tpl_AppendFillBytes(bufName, sizeExpr string, fill byte, depth int, dst *fstringBuilder) {
+	if fill == 0 {
+		dst.WriteFString("%s = append(%s, make([]byte, %s)...)", bufName, bufName, sizeExpr)
+		return
+	}
+	idx := loopVarName(depth)
+	dst.WriteFString("for %s:=0;%s<%s;%s++ {\n", idx, idx, sizeExpr, idx)
+	dst.WriteFString("%s = append(%s, 0x%02X)", bufName, bufName, fill)
+	dst.WriteString("\n}")
+}
+
+// Appends serialized value of expr, which has type t, to the buffer.
+// depth is the nesting level of loops, used to name loop variables.
+func tpl_WriteValue(bufName, expr string, t domain.FieldType, order domain.ByteOrder, depth int, dst *fstringBuilder) error {
+	switch fType := t.(type) {
 	case *domain.SimpleFieldType:
-		tpl_AppendSimpleTypeToBytes(bufName, f.Name(), fType, &sb)
+		tpl_AppendSimpleTypeToBytes(bufName, expr, fType, order, dst)
 
-	case *domain.ArrayFieldType:
-		sb.WriteFString("for i:=0;i<len(o.%s);i++ {\n", f.Name())
-		elType, ok := fType.ElType().(*domain.SimpleFieldType)
-		if !ok {
-			return sb.String(), errors.Join(domain.ErrUnsupportedType, errors.New("array of arrays are not supported"))
+	case *domain.ArrayFieldType, *domain.SliceFieldType:
+		elType := fType.(domain.SequenceFieldType).ElType()
+		idx := loopVarName(depth)
+		dst.WriteFString("for %s:=0;%s<len(%s);%s++ {\n", idx, idx, expr, idx)
+		if err := tpl_WriteValue(bufName, fmt.Sprintf("%s[%s]", expr, idx), elType, order, depth+1, dst); err != nil {
+			return err
 		}
-		tpl_AppendSimpleTypeToBytes("b", fmt.Sprintf("%s[i]", f.Name()), elType, &sb)
-		sb.WriteString("\n}")
+		dst.WriteString("\n}")
 
-	case *domain.SliceFieldType:
-		sb.WriteFString("for i:=0;i<len(o.%s);i++ {\n", f.Name())
-		elType, ok := fType.ElType().(*domain.SimpleFieldType)
-		if !ok {
-			return sb.String(), errors.Join(domain.ErrUnsupportedType, errors.New("slice of arrays are not supported"))
+	case *domain.VarintFieldType:
+		tpl_AppendVarint(bufName, expr, fType, dst)
+
+	case *domain.WireIntFieldType:
+		tpl_AppendWireInt(bufName, expr, fType, order, dst)
+
+	case *domain.CodecFieldType:
+		dst.WriteFString("%s = %s(%s, %s)", bufName, fType.WriteFn(), bufName, expr)
+
+	case *domain.BoolFieldType:
+		dst.WriteFString("if %s {\n%s = append(%s, 1)\n} else {\n%s = append(%s, 0)\n}", expr, bufName, bufName, bufName, bufName)
+
+	case *domain.StringFieldType:
+		tpl_AppendStringToBytes(bufName, expr, fType, order, dst)
+
+	case *domain.StructFieldType:
+		for i := 0; i < fType.FieldCount(); i++ {
+			field := fType.Field(i)
+			if i != 0 {
+				dst.WriteString("\n")
+			}
+			if err := tpl_WriteStructField(bufName, expr, field, order, depth, dst); err != nil {
+				return err
+			}
 		}
-		tpl_AppendSimpleTypeToBytes("b", fmt.Sprintf("%s[i]", f.Name()), elType, &sb)
-		sb.WriteString("\n}")
-	}
 
-	return sb.String(), nil
+	default:
+		return fmt.Errorf("unknown field object type %T", fType)
+	}
+	return nil
 }
 
-func tpl_AppendSimpleTypeToBytes(bufName string, fieldName string, t *domain.SimpleFieldType, dst *fstringBuilder) {
+func tpl_AppendSimpleTypeToBytes(bufName string, expr string, t *domain.SimpleFieldType, order domain.ByteOrder, dst *fstringBuilder) {
 	dst.WriteFString("%s = append(%s, ", bufName, bufName)
 
-	intStr := fmt.Sprintf("uint%d", t.BitSize())
-	for i := 0; i < t.Size(); i++ {
-		dst.WriteString("byte(")
-		if !t.IsInteger() {
-			dst.WriteFString("%s(", intStr)
-		}
-		dst.WriteFString("o.%s", fieldName)
-		if !t.IsInteger() {
-			dst.WriteString(")")
+	value := expr
+	if t.IsFloat() {
+		if t.Underlying() != nil {
+			value = fmt.Sprintf("float%d(%s)", t.BitSize(), value)
 		}
-		if i != 0 {
-			dst.WriteFString(">>%d", i*8)
+		value = fmt.Sprintf("math.Float%dbits(%s)", t.BitSize(), value)
+	}
+	for i := 0; i < t.Size(); i++ {
+		dst.WriteFString("byte(%s", value)
+		if shift := byteShift(i, t.Size(), order); shift != 0 {
+			dst.WriteFString(">>%d", shift)
 		}
 		dst.WriteString(")")
 		if i != t.Size()-1 {
@@ -84,56 +361,415 @@ func tpl_AppendSimpleTypeToBytes(bufName string, fieldName string, t *domain.Sim
 	dst.WriteString(")")
 }
 
-func tpl_ReadField(f domain.StructField, bufName string) (s string, err error) {
-	sb := fstringBuilder{}
+// Packs bit fields of struct objExpr into their container, and appends it to the buffer.
+// Values that don't fit their bits are an error.
+func tpl_AppendBitGroup(bufName, objExpr string, t *domain.BitGroupFieldType, order domain.ByteOrder, dst *fstringBuilder) {
+	c := t.Container()
+	dst.WriteString("{\n")
+	for i := 0; i < t.FieldCount(); i++ {
+		f := t.Field(i)
+		st, ok := f.Type().(*domain.SimpleFieldType)
+		if f.IsPadding() || !ok || f.Bits() == st.BitSize() {
+			continue
+		}
+		expr := objExpr + "." + f.Name()
+		if st.IsSigned() {
+			dst.WriteFString("if %s < -%d || %s > %d {\n", expr, uint64(1)<<(f.Bits()-1), expr, uint64(1)<<(f.Bits()-1)-1)
+		} else {
+			dst.WriteFString("if %s > 0x%X {\n", expr, f.Mask())
+		}
+		dst.WriteFString("return 0, fmt.Errorf(\"%s: value %%d overflows %d bits\", %s)\n", strings.TrimPrefix(expr, "o."), f.Bits(), expr)
+		dst.WriteString("}\n")
+	}
 
-	switch fType := f.Type().(type) {
+	dst.WriteFString("var c %s\n", c.Name())
+	for i := 0; i < t.FieldCount(); i++ {
+		f := t.Field(i)
+		if f.IsPadding() {
+			continue
+		}
+		expr := objExpr + "." + f.Name()
+		shift := ""
+		if f.Shift() > 0 {
+			shift = fmt.Sprintf(" << %d", f.Shift())
+		}
+		st, ok := f.Type().(*domain.SimpleFieldType)
+		if !ok {
+			dst.WriteFString("if %s {\nc |= 1%s\n}\n", expr, shift)
+			continue
+		}
+		val := fmt.Sprintf("%s(%s)", c.Name(), expr)
+		if st.IsSigned() && f.Bits() < c.BitSize() {
+			// Sign bits of negative values are dropped. Unsigned values are already checked to fit
+			val = fmt.Sprintf("%s & 0x%X", val, f.Mask())
+			if shift != "" {
+				val = "(" + val + ")"
+			}
+		}
+		dst.WriteFString("c |= %s%s\n", val, shift)
+	}
+	tpl_AppendSimpleTypeToBytes(bufName, "c", c, order, dst)
+	dst.WriteString("\n}")
+}
 
-	case *domain.SimpleFieldType:
-		sb.WriteFString("o.%s = ", f.Name())
-		tpl_BytesToSimpleType("b", fType, &sb)
+// Appends platform-sized integer expr to the buffer, as its wire type.
+// Values out of range of the wire type are an error.
+func tpl_AppendWireInt(bufName string, expr string, t *domain.WireIntFieldType, order domain.ByteOrder, dst *fstringBuilder) {
+	w := t.Wire()
+	switch {
+	case w.IsSigned() && w.BitSize() < 64:
+		dst.WriteFString("if %s < -%d || %s > %d {\n", expr, uint64(1)<<(w.BitSize()-1), expr, uint64(1)<<(w.BitSize()-1)-1)
+	case !w.IsSigned() && w.BitSize() < 64:
+		// Converted, as the max value may overflow int on 32-bit platforms
+		cond := fmt.Sprintf("uint64(%s) > 0x%X", expr, uint64(1)<<w.BitSize()-1)
+		if t.IsSigned() {
+			cond = fmt.Sprintf("%s < 0 || %s", expr, cond)
+		}
+		dst.WriteFString("if %s {\n", cond)
+	case !w.IsSigned() && t.IsSigned():
+		dst.WriteFString("if %s < 0 {\n", expr)
+	}
+	if w.BitSize() < 64 || (!w.IsSigned() && t.IsSigned()) {
+		dst.WriteFString("return 0, fmt.Errorf(\"%s: value %%d overflows %s\", %s)\n", strings.TrimPrefix(expr, "o."), w.Name(), expr)
+		dst.WriteString("}\n")
+	}
+	tpl_AppendSimpleTypeToBytes(bufName, fmt.Sprintf("%s(%s)", w.Name(), expr), w, order, dst)
+}
 
-	case *domain.ArrayFieldType:
-		elType, ok := fType.ElType().(*domain.SimpleFieldType)
-		if !ok {
-			return sb.String(), errors.Join(domain.ErrUnsupportedType, errors.New("array of arrays are not supported"))
+// Appends integer expr to the buffer, as varint of type t.
+func tpl_AppendVarint(bufName string, expr string, t *domain.VarintFieldType, dst *fstringBuilder) {
+	if t.IsZigzag() {
+		dst.WriteFString("%s = simser.AppendVarint(%s, int64(%s))", bufName, bufName, expr)
+		return
+	}
+	dst.WriteFString("%s = simser.AppendUvarint(%s, uint64(%s))", bufName, bufName, expr)
+}
+
+// Appends string bytes to the buffer, checking that the string fits its encoding.
+func tpl_AppendStringToBytes(bufName string, expr string, t *domain.StringFieldType, order domain.ByteOrder, dst *fstringBuilder) {
+	label := strings.TrimPrefix(expr, "o.")
+
+	switch t.Encoding() {
+	case domain.StringPrefixed:
+		if vt, ok := t.Prefix().(*domain.VarintFieldType); ok {
+			tpl_AppendVarint(bufName, fmt.Sprintf("len(%s)", expr), vt, dst)
+			dst.WriteString("\n")
+			break
+		}
+		prefix := t.Prefix().(*domain.SimpleFieldType)
+		if prefix.Size() < 8 {
+			dst.WriteFString("if uint64(len(%s)) > 0x%X {\n", expr, uint64(1)<<prefix.BitSize()-1)
+			dst.WriteFString("return 0, fmt.Errorf(\"%s: string length %%d overflows %s length prefix\", len(%s))\n",
+				label, prefix.Name(), expr)
+			dst.WriteString("}\n")
 		}
+		tpl_AppendSimpleTypeToBytes(bufName, fmt.Sprintf("%s(len(%s))", prefix.Name(), expr), prefix, order, dst)
+		dst.WriteString("\n")
 
-		sb.WriteFString("o.%s = [%d]%s{}\n", f.Name(), fType.Length(), elType.Name())
+	case domain.StringFixed:
+		dst.WriteFString("if len(%s) > %d {\n", expr, t.BufSize())
+		dst.WriteFString("return 0, fmt.Errorf(\"%s: string length %%d exceeds %d\", len(%s))\n", label, t.BufSize(), expr)
+		dst.WriteString("}\n")
+
+	case domain.StringNulTerminated:
+		strExpr := expr
+		if t.Name() != "string" {
+			strExpr = fmt.Sprintf("string(%s)", expr)
+		}
+		dst.WriteFString("if len(%s) > %d {\n", expr, t.MaxLen())
+		dst.WriteFString("return 0, fmt.Errorf(\"%s: string length %%d exceeds %d\", len(%s))\n", label, t.MaxLen(), expr)
+		dst.WriteString("}\n")
+		dst.WriteFString("if strings.IndexByte(%s, 0) >= 0 {\n", strExpr)
+		dst.WriteFString("return 0, fmt.Errorf(\"%s: string contains NUL byte\")\n", label)
+		dst.WriteString("}\n")
+	}
 
-		sb.WriteFString("for i:=0;i<len(o.%s);i++ {\n", f.Name())
-		sb.WriteFString("o.%s[i] = ", f.Name())
-		tpl_BytesToSimpleType("b", elType, &sb)
+	dst.WriteFString("%s = append(%s, %s...)", bufName, bufName, expr)
+
+	switch t.Encoding() {
+	case domain.StringFixed:
+		dst.WriteFString("\n%s = append(%s, make([]byte, %d-len(%s))...)", bufName, bufName, t.BufSize(), expr)
+	case domain.StringNulTerminated:
+		dst.WriteFString("\n%s = append(%s, 0)", bufName, bufName)
+	}
+}
+
+func tpl_ReadField(f domain.StructField, bufName string, order domain.ByteOrder, src readSource, maxAlloc int) (s string, err error) {
+	sb := fstringBuilder{}
+	if fType, ok := f.Type().(*domain.StringFieldType); ok && fType.Encoding() != domain.StringLenExpr && fType.Encoding() != domain.StringFixed {
+		tpl_ReadString(bufName, "o."+f.Name(), fType, f.ByteOrder(order), src, maxAlloc, &sb)
+		return sb.String(), nil
+	}
+	if fType, ok := f.Type().(*domain.CodecFieldType); ok {
+		tpl_ReadCodec(bufName, "o."+f.Name(), fType, src, maxAlloc, &sb)
+		return sb.String(), nil
+	}
+	if fType, ok := f.Type().(*domain.VarintFieldType); ok {
+		sb.WriteString("{\n")
+		tpl_ReadVarint(bufName, fType, src, fmt.Sprintf("o.%s = %s(v)", f.Name(), fType.Name()), &sb)
 		sb.WriteString("\n}")
+		return sb.String(), nil
+	}
+	if fType, ok := f.Type().(*domain.SliceFieldType); ok {
+		if el, ok := fType.ElType().(*domain.VarintFieldType); ok {
+			tpl_ReadVarintSlice(bufName, "o."+f.Name(), el, src, &sb)
+			return sb.String(), nil
+		}
+		if f.Skip()&domain.SkipRead == 0 {
+			sb.WriteFString("o.%s = make([]%s, sLen)\n", f.Name(), fType.ElType().Name())
+		}
+	}
+	err = tpl_ReadStructField(bufName, "o", f, order, 0, &sb)
+	return sb.String(), err
+}
 
-	case *domain.SliceFieldType:
-		elType, ok := fType.ElType().(*domain.SimpleFieldType)
-		if !ok {
-			return sb.String(), errors.Join(domain.ErrUnsupportedType, errors.New("array of arrays are not supported"))
+// Reads varint of type t from the source into v, and executes assign statement, that uses v.
+// When reading from io.Reader, varint bytes are read into the buffer one by one.
+// v is declared in the current block, so the caller must open a new one.
+func tpl_ReadVarint(bufName string, t *domain.VarintFieldType, src readSource, assign string, dst *fstringBuilder) {
+	decode, valType := "Uvarint", "uint64"
+	if t.IsZigzag() {
+		decode, valType = "Varint", "int64"
+	}
+	bits := t.IntType().BitSize()
+
+	dst.WriteFString("var v %s\n", valType)
+	if src == srcSlice {
+		dst.WriteFString("v, toRead, err = simser.%s(src[n:], %d)\n", decode, bits)
+		dst.WriteString("n += toRead\n")
+		dst.WriteString("if err != nil {\nreturn n, err\n}\n")
+	} else {
+		dst.WriteFString("%s, toRead, err = simser.ReadVarint(r, %s)\n", bufName, bufName)
+		dst.WriteString("n += toRead\n")
+		dst.WriteString("if err != nil {\nreturn n, err\n}\n")
+		dst.WriteFString("if v, _, err = simser.%s(%s[:toRead], %d); err != nil {\nreturn n, err\n}\n", decode, bufName, bits)
+	}
+	dst.WriteString(assign)
+}
+
+// Reads value of codec field from the source, and assigns it to expr.
+// From io.Reader, size of encoded value is not known in advance, so codec read function is called with bytes read
+// so far, and reports how many bytes it needs with io.ErrUnexpectedEOF. They are read at once before the next call.
+// The size is limited by max length of the field and maxAlloc.
+func tpl_ReadCodec(bufName string, expr string, t *domain.CodecFieldType, src readSource, maxAlloc int, dst *fstringBuilder) {
+	label := readErrPrefix(expr)
+	if src == srcSlice {
+		dst.WriteFString("%s, toRead, err = %s(src[n:])\n", expr, t.ReadFn())
+		dst.WriteString("if err != nil {\nreturn n, err\n}\n")
+		dst.WriteString("if toRead < 0 || toRead > len(src)-n {\n")
+		dst.WriteFString("return n, fmt.Errorf(\"%s%s reported %%d bytes read, out of %%d\", toRead, len(src)-n)\n", label, t.ReadFn())
+		dst.WriteString("}\n")
+		dst.WriteString("n += toRead")
+		return
+	}
+
+	maxLen, _ := readLenLimit(t.MaxLen(), 1, maxAlloc)
+	dst.WriteString("{\n")
+	dst.WriteString("k := 0\n")
+	dst.WriteString("toRead = 0\n")
+	dst.WriteString("for {\n")
+	dst.WriteFString("%s, k, err = %s(%s[:toRead])\n", expr, t.ReadFn(), bufName)
+	dst.WriteString("if err == nil {\nbreak\n}\n")
+	dst.WriteString("if err != io.ErrUnexpectedEOF {\nreturn n, err\n}\n")
+	dst.WriteString("if k <= toRead {\n")
+	dst.WriteFString("return n, fmt.Errorf(\"%s%s returned io.ErrUnexpectedEOF, asking for %%d bytes, not more than %%d it got\", k, toRead)\n", label, t.ReadFn())
+	dst.WriteString("}\n")
+	dst.WriteFString("if k > %d {\n", maxLen)
+	dst.WriteFString("return n, fmt.Errorf(\"%sencoded value exceeds %d bytes\")\n", label, maxLen)
+	dst.WriteString("}\n")
+	// Buffer grows like with append, so many small reads don't copy it each time
+	dst.WriteFString("if k > cap(%s) {\n", bufName)
+	dst.WriteFString("%s = append(%s[:toRead], make([]byte, k-toRead)...)\n", bufName, bufName)
+	dst.WriteString("}\n")
+	dst.WriteFString("nRead, err = io.ReadFull(r, %s[toRead:k])\n", bufName)
+	dst.WriteString("n += nRead\n")
+	dst.WriteString("if err != nil {\nreturn n, err\n}\n")
+	dst.WriteString("toRead = k\n")
+	dst.WriteString("}\n")
+	dst.WriteString("if k != toRead {\n")
+	dst.WriteFString("return n, fmt.Errorf(\"%s%s reported %%d bytes read, out of %%d\", k, toRead)\n", label, t.ReadFn())
+	dst.WriteString("}\n")
+	dst.WriteString("}")
+}
+
+// Reads sLen varint elements of a slice expr.
+// From io.Reader, the slice grows as elements are read, as sLen is not backed by data yet.
+// From byte slice, each element takes at least a byte, so the slice is allocated after checking data length.
+func tpl_ReadVarintSlice(bufName, expr string, el *domain.VarintFieldType, src readSource, dst *fstringBuilder) {
+	if src == srcSlice {
+		dst.WriteString("if sLen > len(src)-n {\nreturn n, io.ErrUnexpectedEOF\n}\n")
+		dst.WriteFString("%s = make([]%s, sLen)\n", expr, el.Name())
+		dst.WriteFString("for i := 0; i < len(%s); i++ {\n", expr)
+		tpl_ReadVarint(bufName, el, src, fmt.Sprintf("%s[i] = %s(v)", expr, el.Name()), dst)
+	} else {
+		dst.WriteFString("%s = make([]%s, 0)\n", expr, el.Name())
+		dst.WriteString("for i := 0; i < sLen; i++ {\n")
+		tpl_ReadVarint(bufName, el, src, fmt.Sprintf("%s = append(%s, %s(v))", expr, expr, el.Name()), dst)
+	}
+	dst.WriteString("\n}")
+}
+
+// Deserializes field f of struct objExpr from the buffer, honoring field's skip mode.
+func tpl_ReadStructField(bufName, objExpr string, f domain.StructField, order domain.ByteOrder, depth int, dst *fstringBuilder) error {
+	if f.Skip()&domain.SkipRead != 0 {
+		dst.WriteFString("p += %s", f.Type().SizeExpr())
+		return nil
+	}
+	if bt, ok := f.Type().(*domain.BitGroupFieldType); ok {
+		tpl_ReadBitGroup(bufName, objExpr, bt, f.ByteOrder(order), dst)
+		return nil
+	}
+	return tpl_ReadValue(bufName, objExpr+"."+f.Name(), f.Type(), f.ByteOrder(order), depth, dst)
+}
+
+// Reads container of bit fields from the buffer, and assigns bit fields of struct objExpr.
+// Signed bit fields are sign-extended.
+func tpl_ReadBitGroup(bufName, objExpr string, t *domain.BitGroupFieldType, order domain.ByteOrder, dst *fstringBuilder) {
+	c := t.Container()
+	dst.WriteString("{\nc := ")
+	tpl_BytesToSimpleType(bufName, c, order, dst)
+	dst.WriteString("\n")
+	for i := 0; i < t.FieldCount(); i++ {
+		f := t.Field(i)
+		if f.IsPadding() {
+			continue
+		}
+		expr := objExpr + "." + f.Name()
+		val, masked := "c", "c"
+		if f.Shift() > 0 {
+			val, masked = fmt.Sprintf("c >> %d", f.Shift()), fmt.Sprintf("(c >> %d)", f.Shift())
 		}
-		sb.WriteFString("o.%s = make([]%s, sLen)\n", f.Name(), fType.ElType().Name())
 
-		sb.WriteFString("for i:=0;i<len(o.%s);i++ {\n", f.Name())
-		sb.WriteFString("o.%s[i] = ", f.Name())
-		tpl_BytesToSimpleType("b", elType, &sb)
-		sb.WriteString("\n}")
+		switch ft := f.Type().(type) {
+		case *domain.BoolFieldType:
+			val = fmt.Sprintf("%s&1 != 0", masked)
+			if ft.Name() != "bool" {
+				val = fmt.Sprintf("%s(%s)", ft.Name(), val)
+			}
+		case *domain.SimpleFieldType:
+			if ft.IsSigned() {
+				// Field bits are moved to the top, and shifted back with sign extension
+				signed := fmt.Sprintf("int%d", c.BitSize())
+				val = signed + "(c)"
+				if l := c.BitSize() - f.Shift() - f.Bits(); l > 0 {
+					val = fmt.Sprintf("%s(c << %d)", signed, l)
+				}
+				if r := c.BitSize() - f.Bits(); r > 0 {
+					val = fmt.Sprintf("%s >> %d", val, r)
+				}
+				if ft.Name() != signed {
+					val = fmt.Sprintf("%s(%s)", ft.Name(), val)
+				}
+				break
+			}
+			// Conversion drops high bits, if the field type is as wide as the bit field
+			if f.Shift()+f.Bits() < c.BitSize() && f.Bits() < ft.BitSize() {
+				val = fmt.Sprintf("%s & 0x%X", masked, f.Mask())
+			}
+			if ft.Name() != c.Name() {
+				val = fmt.Sprintf("%s(%s)", ft.Name(), val)
+			}
+		}
+		dst.WriteFString("%s = %s\n", expr, val)
+	}
+	dst.WriteString("}")
+}
+
+// Deserializes value of type t from the buffer at position p, and assigns it to expr.
+// Slices must be allocated beforehand.
+// depth is the nesting level of loops, used to name loop variables.
+func tpl_ReadValue(bufName, expr string, t domain.FieldType, order domain.ByteOrder, depth int, dst *fstringBuilder) error {
+	switch fType := t.(type) {
+	case *domain.SimpleFieldType:
+		dst.WriteFString("%s = ", expr)
+		tpl_BytesToSimpleType(bufName, fType, order, dst)
+
+	case *domain.ArrayFieldType, *domain.SliceFieldType:
+		elType := fType.(domain.SequenceFieldType).ElType()
+		idx := loopVarName(depth)
+		dst.WriteFString("for %s:=0;%s<len(%s);%s++ {\n", idx, idx, expr, idx)
+		if err := tpl_ReadValue(bufName, fmt.Sprintf("%s[%s]", expr, idx), elType, order, depth+1, dst); err != nil {
+			return err
+		}
+		dst.WriteString("\n}")
+
+	case *domain.WireIntFieldType:
+		tpl_ReadWireInt(bufName, expr, fType, order, dst)
+
+	case *domain.BoolFieldType:
+		if fType.IsStrict() {
+			dst.WriteFString("if %s[p] > 1 {\n", bufName)
+			dst.WriteFString("return n, fmt.Errorf(\"%sinvalid bool value %%d\", %s[p])\n", readErrPrefix(expr), bufName)
+			dst.WriteString("}\n")
+		}
+		if fType.Name() == "bool" {
+			dst.WriteFString("%s = %s[p] != 0\n", expr, bufName)
+		} else {
+			dst.WriteFString("%s = %s(%s[p] != 0)\n", expr, fType.Name(), bufName)
+		}
+		dst.WriteString("p += 1")
+
+	case *domain.StringFieldType:
+		tpl_BytesToString(bufName, expr, fType, order, dst)
+
+	case *domain.StructFieldType:
+		for i := 0; i < fType.FieldCount(); i++ {
+			field := fType.Field(i)
+			if i != 0 {
+				dst.WriteString("\n")
+			}
+			if err := tpl_ReadStructField(bufName, expr, field, order, depth, dst); err != nil {
+				return err
+			}
+		}
 
 	default:
-		return sb.String(), fmt.Errorf("unknown field object type %T", fType)
+		return fmt.Errorf("unknown field object type %T", fType)
 	}
+	return nil
+}
 
-	return sb.String(), nil
+// Reads platform-sized integer from the buffer as its wire type, and assigns it to expr.
+// Values out of range of the field type on the current platform are an error.
+func tpl_ReadWireInt(bufName string, expr string, t *domain.WireIntFieldType, order domain.ByteOrder, dst *fstringBuilder) {
+	w := t.Wire()
+	dst.WriteString("{\nv := ")
+	tpl_BytesToSimpleType(bufName, w, order, dst)
+	dst.WriteString("\n")
+
+	// Wire values fit int32 or uint32, but can overflow 32-bit platform types otherwise
+	cond := ""
+	switch {
+	case t.Base() == "int" && w.IsSigned() && w.BitSize() == 64:
+		cond = "v < math.MinInt || v > math.MaxInt"
+	case t.Base() == "int" && !w.IsSigned() && w.BitSize() == 64:
+		cond = "v > math.MaxInt"
+	case t.Base() == "int" && !w.IsSigned() && w.BitSize() == 32:
+		cond = "uint64(v) > math.MaxInt"
+	case t.Base() == "uint" && w.BitSize() == 64:
+		cond = "v > math.MaxUint"
+	case t.Base() == "uintptr" && w.BitSize() == 64:
+		cond = "v > uint64(^uintptr(0))"
+	}
+	if cond != "" {
+		dst.WriteFString("if %s {\n", cond)
+		dst.WriteFString("return n, fmt.Errorf(\"%svalue %%d overflows %s\", v)\n", readErrPrefix(expr), t.Name())
+		dst.WriteString("}\n")
+	}
+	dst.WriteFString("%s = %s(v)\n}", expr, t.Name())
 }
 
-// ftype(b[0] | b[1] << 8 | b[2] << 16 ...)
-func tpl_BytesToSimpleType(bufName string, fType *domain.SimpleFieldType, dst *fstringBuilder) {
+// ftype(b[0] | b[1] << 8 | b[2] << 16 ...) for little-endian,
+// ftype(b[0] << 24 | b[1] << 16 | b[2] << 8 ...) for big-endian.
+// Floats are reinterpreted from bits with math.FloatXXfrombits.
+func tpl_BytesToSimpleType(bufName string, fType *domain.SimpleFieldType, order domain.ByteOrder, dst *fstringBuilder) {
 	uintTypeName := fType.Name()
-	if !fType.IsInteger() {
+	if fType.IsFloat() {
 		uintTypeName = fmt.Sprintf("uint%d", fType.BitSize())
-	}
-
-	if !fType.IsInteger() {
-		dst.WriteFString("%s(", fType.Name())
+		if fType.Underlying() != nil {
+			dst.WriteFString("%s(", fType.Name())
+		}
+		dst.WriteFString("math.Float%dfrombits(", fType.BitSize())
 	}
 
 	for i := 0; i < fType.Size(); i++ {
@@ -142,8 +778,8 @@ func tpl_BytesToSimpleType(bufName string, fType *domain.SimpleFieldType, dst *f
 			dst.WriteFString("+%d", i)
 		}
 		dst.WriteString("])")
-		if i != 0 {
-			dst.WriteFString("<<%d", i*8)
+		if shift := byteShift(i, fType.Size(), order); shift != 0 {
+			dst.WriteFString("<<%d", shift)
 		}
 		if i != fType.Size()-1 {
 			dst.WriteString(" | ")
@@ -152,10 +788,120 @@ func tpl_BytesToSimpleType(bufName string, fType *domain.SimpleFieldType, dst *f
 			dst.WriteString("\n")
 		}
 	}
-	if !fType.IsInteger() {
+	if fType.IsFloat() {
 		dst.WriteString(")")
+		if fType.Underlying() != nil {
+			dst.WriteString(")")
+		}
 	}
 
 	dst.WriteString("\n")
 	dst.WriteFString("p += %d", fType.Size())
 }
+
+// Deserializes string from the buffer and assigns it to expr.
+// Only strings, which size is known before reading them, are supported.
+func tpl_BytesToString(bufName string, expr string, t *domain.StringFieldType, order domain.ByteOrder, dst *fstringBuilder) {
+	switch t.Encoding() {
+	case domain.StringLenExpr:
+		dst.WriteFString("%s = %s(%s[p:p+toRead])\n", expr, t.Name(), bufName)
+		dst.WriteString("p += toRead")
+
+	case domain.StringFixed:
+		dst.WriteFString("%s = %s(bytes.TrimRight(%s[p:p+%d], \"\\x00\"))\n", expr, t.Name(), bufName, t.BufSize())
+		dst.WriteFString("p += %d", t.BufSize())
+
+	default:
+		panic(fmt.Sprintf("string encoding %d cannot be read from buffer. This should be caught earlier. Please, file a bug to the repo.",
+			t.Encoding()))
+	}
+}
+
+// Reads prefixed or NUL-terminated string from the source, and assigns it to expr.
+// Length of strings is limited by their max length and maxAlloc.
+func tpl_ReadString(bufName string, expr string, t *domain.StringFieldType, order domain.ByteOrder, src readSource, maxAlloc int, dst *fstringBuilder) {
+	switch t.Encoding() {
+	case domain.StringPrefixed:
+		if vt, ok := t.Prefix().(*domain.VarintFieldType); ok {
+			dst.WriteString("{\n")
+			tpl_ReadVarint(bufName, vt, src, "p, toRead = 0, int(v)", dst)
+			dst.WriteString("\n}\n")
+		} else {
+			prefix := t.Prefix().(*domain.SimpleFieldType)
+			dst.WriteFString("p, toRead = 0, %d\n", prefix.Size())
+			dst.WriteString(tpl_TakeBytesIntoBuf(bufName, src))
+			dst.WriteString("\n{\nstrLen := ")
+			tpl_BytesToSimpleType(bufName, prefix, order, dst)
+			dst.WriteString("\np, toRead = 0, int(strLen)\n}\n")
+		}
+		dst.WriteString(tpl_CheckReadLen("toRead", t.MaxLen(), 1, maxAlloc))
+		dst.WriteString(tpl_TakeVarBytesIntoBuf(bufName, src))
+		dst.WriteFString("\n%s = %s(%s[:toRead])\n", expr, t.Name(), bufName)
+		dst.WriteString("p += toRead")
+
+	case domain.StringNulTerminated:
+		label := readErrPrefix(expr)
+		maxLen, _ := readLenLimit(t.MaxLen(), 1, maxAlloc)
+		notTerminated := fmt.Sprintf("return n, fmt.Errorf(\"%sstring is not NUL-terminated within %d bytes\")\n", label, maxLen+1)
+
+		if src == srcSlice {
+			dst.WriteFString("%s = src[n:]\n", bufName)
+			dst.WriteFString("if len(%s) > %d {\n%s = %s[:%d]\n}\n", bufName, maxLen+1, bufName, bufName, maxLen+1)
+			dst.WriteFString("toRead = bytes.IndexByte(%s, 0)\n", bufName)
+			dst.WriteString("if toRead < 0 {\n")
+			dst.WriteFString("if len(%s) > %d {\n%s}\n", bufName, maxLen, notTerminated)
+			dst.WriteString("return n, io.ErrUnexpectedEOF\n")
+			dst.WriteString("}\n")
+			dst.WriteFString("%s = %s(%s[:toRead])\n", expr, t.Name(), bufName)
+			dst.WriteString("n += toRead + 1")
+			return
+		}
+
+		dst.WriteString("toRead = 0\n")
+		dst.WriteString("for {\n")
+		dst.WriteFString("if toRead > %d {\n%s}\n", maxLen, notTerminated)
+		dst.WriteFString("if toRead == len(%s) {\n", bufName)
+		dst.WriteFString("%s = append(%s, 0)\n", bufName, bufName)
+		dst.WriteString("}\n")
+		dst.WriteFString("nRead, err = io.ReadFull(r, %s[toRead:toRead+1])\n", bufName)
+		dst.WriteString("n += nRead\n")
+		dst.WriteString("if err != nil {\nreturn n, err\n}\n")
+		dst.WriteFString("if %s[toRead] == 0 {\nbreak\n}\n", bufName)
+		dst.WriteString("toRead++\n")
+		dst.WriteString("}\n")
+		dst.WriteFString("%s = %s(%s[:toRead])", expr, t.Name(), bufName)
+
+	default:
+		panic(fmt.Sprintf("string encoding %d is not read by itself. This should be caught earlier. Please, file a bug to the repo.",
+			t.Encoding()))
+	}
+}
+
+// Bit shift of i-th byte of a value of given size, in serialized byte order.
+func byteShift(i, size int, order domain.ByteOrder) int {
+	if order == domain.BigEndian {
+		return (size - 1 - i) * 8
+	}
+	return i * 8
+}
+
+// Index expressions with loop variables, see loopVarName
+var loopIndexRe = regexp.MustCompile(`\[(i|j|k|i[0-9]+)\]`)
+
+// Returns prefix of read error messages for value expr. Read errors are wrapped into simser.FieldError
+// with the top-level field, so only the path within it is added, e.g. "Flag: " for o.Header.Flag.
+func readErrPrefix(expr string) string {
+	path := loopIndexRe.ReplaceAllString(strings.TrimPrefix(expr, "o."), "")
+	if i := strings.Index(path, "."); i >= 0 {
+		return path[i+1:] + ": "
+	}
+	return ""
+}
+
+// Name of a loop variable for given loop nesting level: i, j, k, i3, i4...
+func loopVarName(depth int) string {
+	if depth < 3 {
+		return string("ijk"[depth])
+	}
+	return fmt.Sprintf("i%d", depth)
+}
@@ -0,0 +1,238 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"github.com/amanofbits/simser/internal/domain"
+	"golang.org/x/tools/go/packages"
+)
+
+// Type-checks generated file f together with the rest of the package.
+// The file that is going to be replaced by f is excluded. Only errors in f are reported,
+// as other package files may depend on code that is being generated.
+func (o Output) typeCheck(f *ast.File) error {
+	files := []*ast.File{f}
+	for i, path := range o.pkg.CompiledGoFiles {
+		if !sameFile(path, o.filename) {
+			files = append(files, o.pkg.Syntax[i])
+		}
+	}
+
+	imp, err := newPackageImporter(o.pkg, f)
+	if err != nil {
+		return err
+	}
+
+	tErrs := []types.Error{}
+	conf := types.Config{
+		Importer: imp,
+		Error: func(err error) {
+			if tErr, ok := err.(types.Error); ok && tErr.Fset.File(tErr.Pos) == tErr.Fset.File(f.Pos()) {
+				tErrs = append(tErrs, tErr)
+			}
+		},
+	}
+	conf.Check(o.pkg.PkgPath, o.pkg.Fset, files, nil) // Errors are collected by conf.Error
+
+	errs := o.explainTypeErrors(f, tErrs)
+	if len(errs) > 0 {
+		return errors.Join(append([]error{errors.New("generated code has type errors")}, errs...)...)
+	}
+	return nil
+}
+
+// Maps type errors in generated file to the struct fields they are generated for.
+// The same tag expression is used by several generated functions, so its errors are reported once,
+// and errors, located only at the struct, are dropped if they are already reported for its field.
+func (o Output) explainTypeErrors(f *ast.File, tErrs []types.Error) []error {
+	fieldMsgs := map[string]bool{}
+	for _, tErr := range tErrs {
+		if structName, fieldNames := o.locateField(f, tErr.Pos); len(fieldNames) > 0 {
+			fieldMsgs[structName+": "+tErr.Msg] = true
+		}
+	}
+	errs, seen := []error{}, map[string]bool{}
+	for _, tErr := range tErrs {
+		if structName, fieldNames := o.locateField(f, tErr.Pos); len(fieldNames) == 0 && fieldMsgs[structName+": "+tErr.Msg] {
+			continue
+		}
+		err := o.explainTypeError(f, tErr)
+		if !seen[err.Error()] {
+			seen[err.Error()] = true
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Maps type error in generated file to the struct fields it is generated for.
+func (o Output) explainTypeError(f *ast.File, tErr types.Error) error {
+	structName, fieldNames := o.locateField(f, tErr.Pos)
+	var s *domain.InputStruct
+	for i := range o.structs {
+		if o.structs[i].Name() == structName {
+			s = &o.structs[i]
+		}
+	}
+	if s == nil {
+		return fmt.Errorf("%s: %s", tErr.Fset.Position(tErr.Pos), tErr.Msg)
+	}
+	if len(fieldNames) == 0 {
+		return fmt.Errorf("struct %s: %s", structName, tErr.Msg)
+	}
+
+	descs := make([]string, 0, len(fieldNames))
+	for _, name := range fieldNames {
+		desc := fmt.Sprintf("'%s.%s'", structName, name)
+		for i := 0; i < s.Type().NumFields(); i++ {
+			if sf := s.Type().Field(i); sf.Name() == name {
+				desc = fmt.Sprintf("'%s.%s %s' with tag `%s`", structName, name, sf.Type(), s.Type().Tag(i))
+			}
+		}
+		descs = append(descs, desc)
+	}
+	what := "field"
+	if len(descs) > 1 {
+		// Bit fields, sharing a container
+		what = "fields"
+	}
+	return fmt.Errorf("%s %s: %s", what, strings.Join(descs, ", "), tErr.Msg)
+}
+
+// Returns receiver type name of generated method, and field names from the field comment
+// that precedes pos in the method. Comment of bit fields, sharing a container, holds their names as 'A|B'.
+func (o Output) locateField(f *ast.File, pos token.Pos) (structName string, fieldNames []string) {
+	for _, decl := range f.Decls {
+		if decl.Pos() > pos || decl.End() < pos {
+			continue
+		}
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) != 1 {
+				return "", nil
+			}
+			recv := d.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				structName = ident.Name
+			}
+		case *ast.GenDecl:
+			// Size constant
+			for _, spec := range d.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok || spec.Pos() > pos || spec.End() < pos {
+					continue
+				}
+				for _, s := range o.structs {
+					if vs.Names[0].Name == s.Name()+"EncodedSize" {
+						return s.Name(), nil
+					}
+				}
+			}
+			return "", nil
+		}
+
+		for _, cg := range f.Comments {
+			if cg.Pos() < decl.Pos() || cg.Pos() > pos {
+				continue
+			}
+			if names, ok := commentFieldNames(cg.Text()); ok {
+				fieldNames = names
+			}
+		}
+		return structName, fieldNames
+	}
+	return "", nil
+}
+
+// Returns field names from field comment text, which is a field name, or names of bit fields joined with '|'.
+func commentFieldNames(text string) (names []string, ok bool) {
+	names = strings.Split(strings.TrimSpace(text), "|")
+	for _, name := range names {
+		if !token.IsIdentifier(name) {
+			return nil, false
+		}
+	}
+	return names, true
+}
+
+// Resolves imports from dependencies of the loaded package.
+// Packages, imported only by generated file f, are loaded separately.
+func newPackageImporter(pkg *packages.Package, f *ast.File) (types.Importer, error) {
+	known := map[string]*types.Package{}
+	var collect func(p *packages.Package)
+	collect = func(p *packages.Package) {
+		for path, dep := range p.Imports {
+			if _, ok := known[path]; !ok && dep.Types != nil {
+				known[path] = dep.Types
+				collect(dep)
+			}
+		}
+	}
+	collect(pkg)
+
+	missing := []string{}
+	for _, imp := range f.Imports {
+		path := strings.Trim(imp.Path.Value, "\"")
+		if _, ok := known[path]; !ok {
+			missing = append(missing, path)
+		}
+	}
+	if len(missing) > 0 {
+		mode := packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedDeps
+		// Packages are resolved within the module of the package
+		dir := ""
+		if len(pkg.CompiledGoFiles) > 0 {
+			dir = filepath.Dir(pkg.CompiledGoFiles[0])
+		}
+		loaded, err := packages.Load(&packages.Config{Mode: mode, Dir: dir, Fset: pkg.Fset}, missing...)
+		if err != nil {
+			return nil, fmt.Errorf("error loading packages %s: %w", missing, err)
+		}
+		for _, p := range loaded {
+			if len(p.Errors) > 0 {
+				return nil, fmt.Errorf("error loading package %s: %v", p.PkgPath, p.Errors[0])
+			}
+			known[p.PkgPath] = p.Types
+		}
+	}
+
+	return importerFunc(func(path string) (*types.Package, error) {
+		if p, ok := known[path]; ok {
+			return p, nil
+		}
+		return nil, fmt.Errorf("package %s is not loaded", path)
+	}), nil
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+func sameFile(a, b string) bool {
+	a, errA := filepath.Abs(a)
+	b, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && a == b
+}
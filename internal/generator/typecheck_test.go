@@ -0,0 +1,87 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/amanofbits/simser/internal/domain"
+)
+
+func TestExplainTypeError(t *testing.T) {
+	const src = "package p\n\n" +
+		"type T struct {\n" +
+		"\tN uint8\n" +
+		"\tA uint8 `simser:\"bits=4\"`\n" +
+		"\t_ uint8 `simser:\"bits=1\"`\n" +
+		"\tB bool  `simser:\"bits=3\"`\n" +
+		"}\n"
+	// Generated code, with field comments
+	const gen = "package p\n\n" +
+		"func (o *T) LoadFrom() {\n" +
+		"\t// N\n" +
+		"\to.N = badN\n\n" +
+		"\t// A|B\n" +
+		"\t{\n" +
+		"\t\tc := o.N\n" +
+		"\t\to.A = c >> 4\n" +
+		"\t\to.B = badB\n" +
+		"\t}\n" +
+		"}\n\n" +
+		// Conditions are repeated in other functions, and size expressions are not under field comments
+		"func (o *T) SaveTo() {\n" +
+		"\t// N\n" +
+		"\to.N = badN\n" +
+		"}\n\n" +
+		"func (o *T) EncodedSize() int {\n" +
+		"\tsize := 1 + badN\n" +
+		"\treturn size + badSize\n" +
+		"}\n"
+
+	fset := token.NewFileSet()
+	srcFile, err := goparser.ParseFile(fset, "t.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genFile, err := goparser.ParseFile(fset, "t.simser.go", gen, goparser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tErrs := []types.Error{}
+	conf := types.Config{Error: func(err error) { tErrs = append(tErrs, err.(types.Error)) }}
+	pkg, _ := conf.Check("example.com/p", fset, []*ast.File{srcFile, genFile}, nil)
+	st := pkg.Scope().Lookup("T").Type().Underlying().(*types.Struct)
+	o := Output{structs: []domain.InputStruct{*domain.NewInputStruct("T", st)}}
+
+	want := []string{
+		"field 'T.N uint8' with tag ``: undefined: badN",
+		"fields 'T.A uint8' with tag `simser:\"bits=4\"`, 'T.B bool' with tag `simser:\"bits=3\"`: undefined: badB",
+		"struct T: undefined: badSize",
+	}
+	errs := o.explainTypeErrors(genFile, tErrs)
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors, want %d: %v", len(errs), len(want), errs)
+	}
+	for i, err := range errs {
+		if got := err.Error(); got != want[i] {
+			t.Errorf("explainTypeErrors()[%d] = %s\nwant %s", i, got, want[i])
+		}
+	}
+}
@@ -28,41 +28,117 @@ import (
 	"golang.org/x/tools/go/packages"
 )
 
+// Parses the package of target file, and selects the file in it.
 func Parse(targetFile string) (file *InputFile, err error) {
 	file = &InputFile{
 		Path: targetFile,
 	}
 
-	file.Modfile, file.Moddir, err = GetClosestModFile(filepath.Dir(targetFile))
+	pkg, err := ParsePackage(filepath.Dir(targetFile))
 	if err != nil {
 		return file, err
 	}
+	file.Modfile, file.Moddir, file.Pkg = pkg.Modfile, pkg.Moddir, pkg.Pkg
 
-	importPath, err := pathToImport(targetFile, file.Moddir, file.Modfile.Module.Mod.Path)
+	file.SyntaxIdx = slices.Index(file.Pkg.CompiledGoFiles, file.Path) // let's hope the order is the same
+	if file.SyntaxIdx < 0 {
+		return file, errors.New("file not found in package. Looks like programming error")
+	}
+
+	return file, nil
+}
+
+// Parses the package in dir.
+func ParsePackage(dir string) (pkg *InputPackage, err error) {
+	pkg = &InputPackage{
+		Dir: dir,
+	}
+
+	pkg.Modfile, pkg.Moddir, err = GetClosestModFile(dir)
 	if err != nil {
-		return file, err
+		return pkg, err
+	}
+
+	importPath, err := pathToImport(dir, pkg.Moddir, pkg.Modfile.Module.Mod.Path)
+	if err != nil {
+		return pkg, err
 	}
 
 	pfset := token.NewFileSet()
-	pkgs, err := loadPackage(importPath, file.Moddir, pfset)
+	pkgs, err := loadPackage(importPath, pkg.Moddir, pfset)
 	if err != nil {
-		return file, err
+		return pkg, err
 	}
 
 	switch len(pkgs) {
 	case 0:
-		return file, errors.New("found 0 file packages")
+		return pkg, errors.New("found 0 packages")
 	case 1:
-		file.Pkg = pkgs[0]
+		pkg.Pkg = pkgs[0]
 	default:
-		return file, fmt.Errorf("%d ambiguous packages found for file", len(pkgs))
+		return pkg, fmt.Errorf("%d ambiguous packages found in %s", len(pkgs), dir)
 	}
-	file.SyntaxIdx = slices.Index(file.Pkg.CompiledGoFiles, file.Path) // let's hope the order is the same
-	if file.SyntaxIdx < 0 {
-		return file, errors.New("file not found in package. Looks like programming error")
+	if len(pkg.Pkg.Syntax) != len(pkg.Pkg.CompiledGoFiles) {
+		return pkg, fmt.Errorf("package %s has %d files, but %d parsed", pkg.Pkg.PkgPath, len(pkg.Pkg.CompiledGoFiles), len(pkg.Pkg.Syntax))
 	}
 
-	return file, nil
+	return pkg, nil
+}
+
+type InputPackage struct {
+	Dir     string            // Directory of the package
+	Modfile *modfile.File     // Package's module
+	Moddir  string            // Directory where module file is present
+	Pkg     *packages.Package // Parsed package
+}
+
+// Returns all files of the package.
+func (p InputPackage) Files() []InputFile {
+	files := make([]InputFile, len(p.Pkg.CompiledGoFiles))
+	for i, path := range p.Pkg.CompiledGoFiles {
+		files[i] = InputFile{
+			Path:      path,
+			Modfile:   p.Modfile,
+			Moddir:    p.Moddir,
+			Pkg:       p.Pkg,
+			SyntaxIdx: i,
+		}
+	}
+	return files
+}
+
+// Input structs, declared in a single file.
+type FileStructs struct {
+	File    InputFile
+	Structs []domain.InputStruct
+}
+
+// Returns input structs of all package files. Files without input structs are omitted.
+func (p InputPackage) GetInputStructs(acceptor TypeAcceptor) ([]FileStructs, error) {
+	found := []FileStructs{}
+	count := 0
+	for _, f := range p.Files() {
+		if acceptor.IsDrained() {
+			break
+		}
+		filtered, err := f.filterInputStructs(acceptor)
+		if err != nil {
+			return nil, err
+		}
+		if len(filtered) == 0 {
+			continue
+		}
+		structs, err := analyzeStructs(filtered, f)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, FileStructs{File: f, Structs: structs})
+		count += len(structs)
+	}
+	if err := checkAcceptor(acceptor, count); err != nil {
+		return nil, err
+	}
+	return found, nil
 }
 
 type InputFile struct {
@@ -90,16 +166,24 @@ func (f InputFile) GetInputStructs(acceptor TypeAcceptor) ([]domain.InputStruct,
 	if err != nil {
 		return nil, err
 	}
-	if !acceptor.IsDrained() && !acceptor.AcceptsAll() {
-		return nil, fmt.Errorf("types '%s' were requested but not found", acceptor)
-	}
-	if len(filtered) == 0 {
-		return nil, fmt.Errorf("no types %s found", acceptor)
+	if err := checkAcceptor(acceptor, len(filtered)); err != nil {
+		return nil, err
 	}
 
 	return analyzeStructs(filtered, f)
 }
 
+// Returns error if not all requested types were found.
+func checkAcceptor(acceptor TypeAcceptor, found int) error {
+	if !acceptor.IsDrained() && !acceptor.AcceptsAll() {
+		return fmt.Errorf("types '%s' were requested but not found", acceptor)
+	}
+	if found == 0 {
+		return fmt.Errorf("no types %s found", acceptor)
+	}
+	return nil
+}
+
 type filteredStruct struct {
 	name     string
 	astType  *ast.StructType
@@ -153,11 +237,10 @@ func (f InputFile) filterInputStructs(acceptor TypeAcceptor) (structs []filtered
 	return structs, nil
 }
 
-func pathToImport(file, moddir, modname string) (string, error) {
-	path, err := filepath.Rel(moddir, file)
+func pathToImport(dir, moddir, modname string) (string, error) {
+	path, err := filepath.Rel(moddir, dir)
 	if err != nil {
 		return "", err
 	}
-	path = filepath.ToSlash(filepath.Join(modname, path))
-	return filepath.Dir(path), nil
+	return filepath.ToSlash(filepath.Join(modname, path)), nil
 }
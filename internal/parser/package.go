@@ -24,7 +24,7 @@ import (
 
 func loadPackage(importName, moddir string, fset *token.FileSet) ([]*packages.Package, error) {
 	cfg := &packages.Config{
-		Mode: packages.NeedSyntax | packages.NeedCompiledGoFiles | packages.NeedDeps | packages.NeedFiles | packages.NeedTypesInfo | packages.NeedTypesSizes | packages.NeedTypes | packages.NeedName,
+		Mode: packages.NeedSyntax | packages.NeedCompiledGoFiles | packages.NeedDeps | packages.NeedImports | packages.NeedFiles | packages.NeedTypesInfo | packages.NeedTypesSizes | packages.NeedTypes | packages.NeedName,
 		Dir:  moddir,
 		Fset: fset,
 	}
@@ -17,7 +17,13 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
 	"go/types"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/amanofbits/simser/internal/domain"
@@ -40,28 +46,471 @@ func analyzeStructs(filtered []filteredStruct, f InputFile) (structs []domain.In
 func analyzeStruct(fs filteredStruct, pkgPath string) (s *domain.InputStruct, err error) {
 
 	s = domain.NewInputStruct(fs.name, fs.typeInfo)
-	fields := make([]domain.StructField, fs.fieldCount())
+	fields, err := analyzeFields(fs.name, fs.typeInfo, "o", pkgPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.SetFields(fields)
+	return s, nil
+}
 
-	for i := 0; i < fs.fieldCount(); i++ {
-		sField := fs.typeInfo.Field(i)
+// Analyzes fields of a struct, accessible in generated code as objExpr.
+// nesting holds named structs that are currently being analyzed, to detect recursive types.
+func analyzeFields(structName string, st *types.Struct, objExpr string, pkgPath string, nesting []*types.Named) (fields []domain.StructField, err error) {
+	fields = make([]domain.StructField, 0, st.NumFields())
+	group := bitGroup{}
+
+	for i := 0; i < st.NumFields(); i++ {
+		sField := st.Field(i)
 		tag := *newStructTag()
-		if err := tag.parse(fs.astType.Fields.List[i].Tag); err != nil {
+		if err := tag.parse(st.Tag(i)); err != nil {
 			return nil, err
 		}
+		if tag.isSkipped() {
+			continue
+		}
 
-		fTyp, err := getFieldType(sField.Type(), pkgPath, &tag)
+		bits, container, ok, err := tag.getBits()
+		if err != nil {
+			return nil, fmt.Errorf("field '%s.%s %s': %w", structName, sField.Name(), sField.Type(), err)
+		}
+		if ok {
+			if err := group.add(sField, &tag, bits, container, pkgPath); err != nil {
+				return nil, fmt.Errorf("field '%s.%s %s': %w", structName, sField.Name(), sField.Type(), err)
+			}
+			if field, ok := group.take(); ok {
+				fields = append(fields, field)
+			}
+			continue
+		}
+		if err := group.checkTaken(structName); err != nil {
+			return nil, err
+		}
 
-		field := domain.NewStructField(sField.Name(), fTyp, tag.values)
+		field, err := analyzeField(sField, &tag, objExpr, pkgPath, nesting)
 		if err != nil {
-			return nil, fmt.Errorf("field '%s.%s %s': %w", s.Name(), sField.Name(), sField.Type(), err)
+			return nil, fmt.Errorf("field '%s.%s %s': %w", structName, sField.Name(), sField.Type(), err)
 		}
-		fields[i] = field
+		fields = append(fields, field)
 	}
-	s.SetFields(fields)
-	return s, nil
+	if err := group.checkTaken(structName); err != nil {
+		return nil, err
+	}
+	if err := linkLenFields(structName, fields, objExpr == "o"); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// Adjacent bit fields, collected until they fill a container of 8, 16, 32 or 64 bits.
+// Container size can be set on the first field, otherwise the group is closed at the first of these sizes.
+type bitGroup struct {
+	fields    []domain.BitField
+	bits      int
+	container int // Container size in bits, if set
+	bitOrder  domain.BitOrder
+	byteOrder domain.ByteOrder
+}
+
+func (g *bitGroup) add(sField *types.Var, tag *structTag, bits, container int, pkgPath string) error {
+	for _, key := range []string{"if", "const", "lenof", "rskip", "wskip", "codec"} {
+		if _, ok := tag.values[key]; !ok {
+			continue
+		}
+		if key == "rskip" || key == "wskip" {
+			return fmt.Errorf("'%s' cannot be set on bit fields. Use blank (`_`) bit fields for reserved bits", key)
+		}
+		return fmt.Errorf("'%s' cannot be set on bit fields", key)
+	}
+	typ, err := getFieldType(sField.Type(), "", pkgPath, tag, nil)
+	if err != nil {
+		return err
+	}
+	switch t := typ.(type) {
+	case *domain.SimpleFieldType:
+		if !t.IsInteger() {
+			return errors.Join(domain.ErrUnsupportedType, errors.New("'bits' can be set only on integer and bool fields"))
+		}
+		if bits > t.BitSize() {
+			return fmt.Errorf("%d bits do not fit into %s", bits, t.Name())
+		}
+	case *domain.BoolFieldType:
+		if bits != 1 {
+			return fmt.Errorf("bool bit field must take 1 bit, got %d", bits)
+		}
+	default:
+		return errors.Join(domain.ErrUnsupportedType, errors.New("'bits' can be set only on integer and bool fields"))
+	}
+
+	bitOrder, hasBitOrder, err := tag.getBitOrder()
+	if err != nil {
+		return err
+	}
+	byteOrder, err := tag.getByteOrder()
+	if err != nil {
+		return err
+	}
+	if len(g.fields) == 0 {
+		g.bitOrder, g.byteOrder, g.container = bitOrder, byteOrder, container
+	} else if (hasBitOrder && bitOrder != g.bitOrder) || (byteOrder != domain.DefaultByteOrder && byteOrder != g.byteOrder) {
+		return errors.New("bit and byte order of bit fields must be set on the first field of the container")
+	} else if container != 0 {
+		return errors.New("container size of bit fields must be set on the first field of the container")
+	}
+
+	g.fields = append(g.fields, domain.NewBitField(sField.Name(), typ, bits))
+	g.bits += bits
+	if g.container != 0 && g.bits > g.container {
+		return fmt.Errorf("bit fields take %d bits, which is more than their container of %d bits", g.bits, g.container)
+	}
+	if g.bits > 64 {
+		return fmt.Errorf("bit fields take %d bits, which is more than 64", g.bits)
+	}
+	return nil
+}
+
+// Returns struct field of bit fields, if they fill a container, and resets the group.
+// Fields are named after non-blank bit fields, or '_' if all of them are blank, which makes the field padding.
+func (g *bitGroup) take() (field domain.StructField, ok bool) {
+	if g.container != 0 && g.bits != g.container {
+		return field, false
+	}
+	if g.bits != 8 && g.bits != 16 && g.bits != 32 && g.bits != 64 {
+		return field, false
+	}
+	names := []string{}
+	for _, f := range g.fields {
+		if !f.IsPadding() {
+			names = append(names, f.Name())
+		}
+	}
+	name := strings.Join(names, "|")
+	if name == "" {
+		name = "_"
+	}
+
+	field = domain.NewStructField(name, domain.NewBitGroupFieldType(g.fields, g.bitOrder), g.byteOrder, nil)
+	if field.IsPadding() {
+		field.SetSkip(domain.SkipRead | domain.SkipWrite)
+	}
+	*g = bitGroup{}
+	return field, true
+}
+
+// Fails if there are bit fields, that don't fill a container.
+func (g bitGroup) checkTaken(structName string) error {
+	if len(g.fields) == 0 {
+		return nil
+	}
+	if g.container != 0 {
+		return fmt.Errorf("bit fields of %s, starting with '%s', take %d bits, which doesn't fill their container of %d bits. "+
+			"Use blank (`_`) bit fields for reserved bits", structName, g.fields[0].Name(), g.bits, g.container)
+	}
+	return fmt.Errorf("bit fields of %s, starting with '%s', take %d bits, which doesn't fill a container of 8, 16, 32 or 64 bits. "+
+		"Use blank (`_`) bit fields for reserved bits", structName, g.fields[0].Name(), g.bits)
+}
+
+func analyzeField(sField *types.Var, tag *structTag, objExpr string, pkgPath string, nesting []*types.Named) (field domain.StructField, err error) {
+	for _, key := range []string{"len", "if"} {
+		if expr, ok := tag.values[key]; ok {
+			if err := checkExprNames(key, expr, sField.Pkg()); err != nil {
+				return field, err
+			}
+		}
+	}
+	codec, hasCodec, err := tag.getCodec()
+	if err != nil {
+		return field, err
+	}
+	var fTyp domain.FieldType
+	if hasCodec {
+		fTyp, err = getCodecFieldType(sField, codec, objExpr+"."+sField.Name(), pkgPath, tag)
+	} else {
+		fTyp, err = getFieldType(sField.Type(), objExpr+"."+sField.Name(), pkgPath, tag, nesting)
+	}
+	if err != nil {
+		return field, err
+	}
+	order, err := tag.getByteOrder()
+	if err != nil {
+		return field, err
+	}
+	pad, err := tag.getPadByte()
+	if err != nil {
+		return field, err
+	}
+
+	field = domain.NewStructField(sField.Name(), fTyp, order, tag.values)
+	field.SetSkip(tag.getSkipMode())
+	field.SetFillByte(pad)
+	if field.IsPadding() {
+		if !domain.IsFixedSize(field) {
+			return field, errors.Join(domain.ErrUnsupportedType, errors.New("padding field must have fixed size"))
+		}
+		field.SetSkip(domain.SkipRead | domain.SkipWrite)
+	}
+	if lenOf, ok := tag.getLenOf(); ok {
+		if !isIntegerField(fTyp) {
+			return field, errors.New("'lenof' can be set only on integer fields")
+		}
+		field.SetLenOf(lenOf)
+	}
+	cond, ok, err := tag.getCondExpr()
+	if err != nil {
+		return field, err
+	}
+	if ok {
+		if objExpr != "o" {
+			return field, errors.Join(domain.ErrUnsupportedType, errors.New("fields of nested structs cannot be conditional"))
+		}
+		field.SetCond(cond)
+	}
+	if raw, ok := tag.getConst(); ok {
+		if objExpr != "o" {
+			return field, errors.Join(domain.ErrUnsupportedType, errors.New("fields of nested structs cannot be constant"))
+		}
+		if field.Skip() != 0 || field.LenOf() != "" {
+			return field, errors.New("constant fields cannot be skipped, be padding or hold length. Use 'pad' for padding value")
+		}
+		c, err := getFieldConst(raw, fTyp)
+		if err != nil {
+			return field, err
+		}
+		field.SetConst(c)
+	}
+	if st, ok := fTyp.(*domain.StringFieldType); ok && field.Skip() != 0 {
+		if st.Encoding() == domain.StringPrefixed || st.Encoding() == domain.StringNulTerminated {
+			return field, errors.Join(domain.ErrUnsupportedType, errors.New("prefixed and NUL-terminated strings cannot be skipped on read or write"))
+		}
+	}
+	if isVarintField(fTyp) && field.Skip() != 0 {
+		return field, errors.Join(domain.ErrUnsupportedType, errors.New("varint fields cannot be skipped on read or write"))
+	}
+	if hasCodec && field.Skip() != 0 {
+		return field, errors.Join(domain.ErrUnsupportedType, errors.New("codec fields cannot be skipped on read or write"))
+	}
+	return field, nil
+}
+
+// Returns true for fixed-size, platform-sized and varint integers
+func isIntegerField(t domain.FieldType) bool {
+	switch typ := t.(type) {
+	case *domain.SimpleFieldType:
+		return typ.IsInteger()
+	case *domain.VarintFieldType, *domain.WireIntFieldType:
+		return true
+	}
+	return false
+}
+
+// Returns true for varints and slices of them
+func isVarintField(t domain.FieldType) bool {
+	if st, ok := t.(*domain.SliceFieldType); ok {
+		t = st.ElType()
+	}
+	_, ok := t.(*domain.VarintFieldType)
+	return ok
+}
+
+// valueExpr is an expression to access the value in generated code, or empty if there is no direct access,
+// e.g. for sequence elements.
+// Links length fields with sequences and strings, whose length they hold.
+// Fields with 'lenof' tag are linked explicitly, and, if autoLink is set, integer fields referred by
+// length expressions as 'o.Field' or 'int(o.Field)' are linked automatically (unless referred more than once).
+func linkLenFields(structName string, fields []domain.StructField, autoLink bool) error {
+	idx := map[string]int{}
+	for i, f := range fields {
+		idx[f.Name()] = i
+	}
+
+	linked := map[string]bool{}
+	for _, f := range fields {
+		if f.LenOf() == "" {
+			continue
+		}
+		lenExpr, hasLen := "", false
+		if ti, ok := idx[f.LenOf()]; ok {
+			lenExpr, hasLen = lenExprOf(fields[ti].Type())
+		}
+		if !hasLen {
+			return fmt.Errorf("field '%s.%s': 'lenof' refers to '%s', which is not a slice or 'len' string field of the struct",
+				structName, f.Name(), f.LenOf())
+		}
+		if name, ok := lenExprField(lenExpr); !ok || name != f.Name() {
+			return fmt.Errorf("field '%s.%s': 'lenof' refers to '%s', whose length expression '%s' is not 'o.%s' or 'int(o.%s)'",
+				structName, f.Name(), f.LenOf(), lenExpr, f.Name(), f.Name())
+		}
+		if linked[f.LenOf()] {
+			return fmt.Errorf("field '%s.%s': length of '%s' is already held by another field", structName, f.Name(), f.LenOf())
+		}
+		linked[f.LenOf()] = true
+	}
+	if !autoLink {
+		return nil
+	}
+
+	counters := map[string][]string{} // counter field -> fields referring to it in length expressions
+	for _, f := range fields {
+		lenExpr, ok := lenExprOf(f.Type())
+		if linked[f.Name()] || !ok {
+			continue
+		}
+		if counter, ok := lenExprField(lenExpr); ok {
+			counters[counter] = append(counters[counter], f.Name())
+		}
+	}
+	for counter, targets := range counters {
+		ci, ok := idx[counter]
+		if !ok || len(targets) != 1 || fields[ci].LenOf() != "" || fields[ci].Const() != nil {
+			continue
+		}
+		if isIntegerField(fields[ci].Type()) {
+			fields[ci].SetLenOf(targets[0])
+		}
+	}
+	return nil
+}
+
+// Parses constant value of integer field, or of byte array field, given as quoted string.
+func getFieldConst(raw string, t domain.FieldType) (*domain.FieldConst, error) {
+	switch typ := t.(type) {
+	case *domain.VarintFieldType:
+		return nil, errors.Join(domain.ErrUnsupportedType, errors.New("varint fields cannot be constant"))
+
+	case *domain.WireIntFieldType:
+		return nil, errors.Join(domain.ErrUnsupportedType, errors.New("platform-sized integer fields cannot be constant, use fixed-size type instead"))
+
+	case *domain.SimpleFieldType:
+		if !typ.IsInteger() {
+			break
+		}
+		var bits uint64
+		if typ.IsSigned() {
+			v, err := strconv.ParseInt(raw, 0, typ.Size()*8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constant '%s' for %s, %w", raw, typ.Name(), err)
+			}
+			bits = uint64(v)
+		} else {
+			v, err := strconv.ParseUint(raw, 0, typ.Size()*8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constant '%s' for %s, %w", raw, typ.Name(), err)
+			}
+			bits = v
+		}
+		return domain.NewIntFieldConst(fmt.Sprintf("%s(%s)", typ.Name(), raw), bits, typ.Size()), nil
+
+	case *domain.ArrayFieldType:
+		el, ok := typ.ElType().(*domain.SimpleFieldType)
+		if !ok || !el.IsInteger() || el.Size() != 1 {
+			break
+		}
+		val, err := strconv.Unquote(raw)
+		if err != nil {
+			return nil, fmt.Errorf("constant of byte array must be a quoted string, got '%s'", raw)
+		}
+		if len(val) != typ.Size() {
+			return nil, fmt.Errorf("constant %s has %d bytes, but array has %d", raw, len(val), typ.Size())
+		}
+		elems := make([]string, len(val))
+		for i := 0; i < len(val); i++ {
+			if el.IsSigned() {
+				elems[i] = strconv.Itoa(int(int8(val[i])))
+			} else {
+				elems[i] = fmt.Sprintf("0x%02X", val[i])
+			}
+		}
+		return domain.NewBytesFieldConst(fmt.Sprintf("%s{%s}", typ.Name(), strings.Join(elems, ", ")), []byte(val)), nil
+	}
+	return nil, errors.Join(domain.ErrUnsupportedType, errors.New("'const' can be set only on integer or byte array fields"))
+}
+
+// Returns length expression, if length of the type is set by one
+func lenExprOf(t domain.FieldType) (expr string, ok bool) {
+	switch typ := t.(type) {
+	case *domain.SliceFieldType:
+		return typ.LenExpr(), true
+	case *domain.StringFieldType:
+		return typ.LenExpr(), typ.Encoding() == domain.StringLenExpr
+	}
+	return "", false
 }
 
-func getFieldType(t types.Type, trimPkgPath string, tag *structTag) (ft domain.FieldType, err error) {
+// Returns field name, if expression is a plain 'o.Field' or 'int(o.Field)'
+func lenExprField(expr string) (name string, ok bool) {
+	e, err := goparser.ParseExpr(expr)
+	if err != nil {
+		return "", false
+	}
+	if call, ok := e.(*ast.CallExpr); ok {
+		fn, ok := call.Fun.(*ast.Ident)
+		if !ok || fn.Name != "int" || len(call.Args) != 1 {
+			return "", false
+		}
+		e = call.Args[0]
+	}
+	sel, ok := e.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	if obj, ok := sel.X.(*ast.Ident); !ok || obj.Name != "o" {
+		return "", false
+	}
+	return sel.Sel.Name, true
+}
+
+// Names of variables, declared by generated functions, including loop variables i, j, k, i3, i4...
+// Tag expressions are placed into these functions, so package-level names would be shadowed by them.
+var generatedLocals = regexp.MustCompile(`^(b|buf|c|data|dst|err|errField|errOffset|n|nRead|ok|p|r|` +
+	`sElSize|sLen|size|src|strLen|toRead|v|w|i|j|k|i[0-9]+)$`)
+
+// Checks that tag expression refers only to the object being serialized, as 'o',
+// and to package-level and predeclared names, which are not shadowed in generated code.
+func checkExprNames(key, expr string, pkg *types.Package) (err error) {
+	e, pErr := goparser.ParseExpr(expr)
+	if pErr != nil {
+		return fmt.Errorf("invalid '%s' expression '%s', %w", key, expr, pErr)
+	}
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		if err != nil {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			// Selected names are fields, methods or package members
+			ast.Inspect(n.X, visit)
+			return false
+		case *ast.Ident:
+			switch name := n.Name; {
+			case name == "o":
+			case generatedLocals.MatchString(name):
+				err = fmt.Errorf("'%s' expression '%s' refers to '%s', which is a variable of generated code. "+
+					"Use 'o.Field' to refer to fields", key, expr, name)
+			case pkg != nil && pkg.Scope().Lookup(name) != nil:
+			case types.Universe.Lookup(name) != nil:
+			default:
+				err = fmt.Errorf("'%s' expression '%s' refers to '%s', which is neither 'o' nor a package-level name", key, expr, name)
+			}
+		}
+		return true
+	}
+	ast.Inspect(e, visit)
+	return err
+}
+
+func getFieldType(t types.Type, valueExpr string, trimPkgPath string, tag *structTag, nesting []*types.Named) (ft domain.FieldType, err error) {
+	if isString(t) {
+		return getStringFieldType(t, valueExpr, trimPkgPath, tag)
+	}
+	if isBool(t) {
+		name, err := getTypeName(t, trimPkgPath)
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewBoolFieldType(name, tag.isStrict()), nil
+	}
+
 	switch typ := t.(type) {
 
 	case *types.Basic:
@@ -69,17 +518,35 @@ func getFieldType(t types.Type, trimPkgPath string, tag *structTag) (ft domain.F
 		if err != nil {
 			return nil, err
 		}
+		wire, ok, err := tag.getWireType()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return getWireIntFieldType(typ, name, wire, tag)
+		}
 		size, err := getTypeSize(typ)
 		if err != nil {
 			return nil, err
 		}
-		return domain.NewSimpleFieldType(name, size, nil), nil
+		return getIntFieldType(domain.NewSimpleFieldType(name, size, nil), valueExpr, tag)
 
 	case *types.Named:
+		if st, ok := typ.Underlying().(*types.Struct); ok {
+			return getNamedStructFieldType(typ, st, valueExpr, trimPkgPath, nesting)
+		}
+
 		name, err := getTypeName(typ, trimPkgPath)
 		if err != nil {
 			return nil, err
 		}
+		wire, ok, err := tag.getWireType()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return getWireIntFieldType(typ, name, wire, tag)
+		}
 		size, err := getTypeSize(typ)
 		if err != nil {
 			return nil, err
@@ -93,20 +560,34 @@ func getFieldType(t types.Type, trimPkgPath string, tag *structTag) (ft domain.F
 		if !ok {
 			return nil, errors.Join(domain.ErrUnsupportedType, fmt.Errorf("underlying type is not a basic type, but %T", underlying))
 		}
-		return domain.NewSimpleFieldType(
+		return getIntFieldType(domain.NewSimpleFieldType(
 			name,
 			size,
 			domain.NewSimpleFieldType(ut.Name(), size, nil),
-		), nil
+		), valueExpr, tag)
+
+	case *types.Struct:
+		name, err := getTypeName(typ, trimPkgPath)
+		if err != nil {
+			return nil, err
+		}
+		fields, err := analyzeFields("struct", typ, valueExpr, trimPkgPath, nesting)
+		if err != nil {
+			return nil, err
+		}
+		return newNestedStructFieldType(name, fields)
 
 	case *types.Array:
 		if typ.Len() < 0 {
 			return nil, errors.Join(domain.ErrUnsupportedType, fmt.Errorf("array type with unknown length, %T, %v", typ, typ))
 		}
-		el, err := getFieldType(typ.Elem(), trimPkgPath, tag)
+		el, err := getFieldType(typ.Elem(), "", trimPkgPath, tag, nesting)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get array element type, %w", err)
 		}
+		if !domain.IsFixedSize(el.Size()) {
+			return nil, errors.Join(domain.ErrUnsupportedType, errors.New("array elements of variable size are not supported"))
+		}
 		return domain.NewArrayFieldType(int(typ.Len()), el), nil
 
 	case *types.Slice:
@@ -122,21 +603,205 @@ func getFieldType(t types.Type, trimPkgPath string, tag *structTag) (ft domain.F
 			return nil, errors.Join(domain.ErrUnsupportedType, errors.New("empty length expression"))
 		}
 
+		maxLen, _, err := tag.getMaxLen()
 		if err != nil {
 			return nil, errors.Join(domain.ErrUnsupportedType, err)
 		}
 
-		el, err := getFieldType(typ.Elem(), trimPkgPath, tag)
+		el, err := getFieldType(typ.Elem(), "", trimPkgPath, tag.without("len"), nesting)
 		if err != nil {
-			return nil, errors.Join(domain.ErrUnsupportedType, fmt.Errorf("failed to get slice element type, %T", typ.Elem()))
+			return nil, errors.Join(domain.ErrUnsupportedType, fmt.Errorf("failed to get slice element type, %T, %w", typ.Elem(), err))
+		}
+		if _, isVarint := el.(*domain.VarintFieldType); !isVarint && !domain.IsFixedSize(el.Size()) {
+			return nil, errors.Join(domain.ErrUnsupportedType, errors.New("slice elements of variable size are not supported"))
 		}
-		return domain.NewSliceFieldType(lenTag, el), nil
+		return domain.NewSliceFieldType(lenTag, maxLen, el), nil
 
 	default:
 		return nil, errors.Join(domain.ErrUnsupportedType, fmt.Errorf("%T, %v", typ, typ))
 	}
 }
 
+// Returns varint type for integer type st, if varint encoding is selected with the tag, or st itself otherwise.
+func getIntFieldType(st *domain.SimpleFieldType, valueExpr string, tag *structTag) (domain.FieldType, error) {
+	varint, zigzag, err := tag.getVarint()
+	if err != nil || !varint {
+		return st, err
+	}
+	if !st.IsInteger() {
+		return nil, errors.Join(domain.ErrUnsupportedType, errors.New("'varint' and 'zigzag' can be set only on integer fields"))
+	}
+	if zigzag && !st.IsSigned() {
+		return nil, errors.New("'zigzag' can be set only on signed integers, use 'varint' for unsigned ones")
+	}
+	if !zigzag && st.IsSigned() {
+		return nil, errors.New("'varint' can be set only on unsigned integers, use 'zigzag' for signed ones")
+	}
+	return domain.NewVarintFieldType(st, zigzag, valueExpr), nil
+}
+
+// Default max size of encoded value of codec fields, read from io.Reader, if not set with 'max' tag attribute
+const defaultCodecMaxLen = 4096
+
+// Returns type of field, [de]serialized by codec functions <codec>Read and <codec>Write from the package of the field.
+// Signatures of the functions are checked here, to report mismatches with the field type clearly.
+func getCodecFieldType(sField *types.Var, codec string, valueExpr string, trimPkgPath string, tag *structTag) (domain.FieldType, error) {
+	for _, key := range []string{"len", "prefix", "fixed", "cstr", "varint", "zigzag", "as", "strict"} {
+		if _, ok := tag.values[key]; ok {
+			return nil, fmt.Errorf("'%s' cannot be combined with 'codec', as the field is encoded by the codec", key)
+		}
+	}
+	maxLen, hasMaxLen, err := tag.getMaxLen()
+	if err != nil {
+		return nil, err
+	}
+	if !hasMaxLen {
+		maxLen = defaultCodecMaxLen
+	}
+
+	t := sField.Type()
+	byteSlice := types.NewSlice(types.Universe.Lookup("byte").Type())
+	errType := types.Universe.Lookup("error").Type()
+	newTuple := func(ts ...types.Type) *types.Tuple {
+		vars := make([]*types.Var, len(ts))
+		for i, t := range ts {
+			vars[i] = types.NewParam(token.NoPos, nil, "", t)
+		}
+		return types.NewTuple(vars...)
+	}
+	fns := []struct {
+		name string
+		sig  *types.Signature
+	}{
+		{codec + "Read", types.NewSignatureType(nil, nil, nil, newTuple(byteSlice), newTuple(t, types.Typ[types.Int], errType), false)},
+		{codec + "Write", types.NewSignatureType(nil, nil, nil, newTuple(byteSlice, t), newTuple(byteSlice), false)},
+	}
+	qualifier := localQualifier(trimPkgPath)
+	for _, fn := range fns {
+		obj := sField.Pkg().Scope().Lookup(fn.name)
+		if obj == nil {
+			return nil, fmt.Errorf("codec function %s is not found in package %s, expected %s",
+				fn.name, sField.Pkg().Path(), types.TypeString(fn.sig, qualifier))
+		}
+		if _, ok := obj.(*types.Func); !ok || !types.Identical(obj.Type(), fn.sig) {
+			return nil, fmt.Errorf("codec function %s has type %s, expected %s",
+				fn.name, types.TypeString(obj.Type(), qualifier), types.TypeString(fn.sig, qualifier))
+		}
+	}
+	return domain.NewCodecFieldType(types.TypeString(t, qualifier), codec, valueExpr, maxLen), nil
+}
+
+// Returns type of platform-sized integer t, serialized as fixed-size wire type, set with 'as' tag.
+func getWireIntFieldType(t types.Type, name string, wire *domain.SimpleFieldType, tag *structTag) (domain.FieldType, error) {
+	bt, ok := t.Underlying().(*types.Basic)
+	if !ok || (bt.Kind() != types.Int && bt.Kind() != types.Uint && bt.Kind() != types.Uintptr) {
+		return nil, errors.Join(domain.ErrUnsupportedType, errors.New("'as' can be set only on int, uint and uintptr fields, other types have fixed size"))
+	}
+	varint, _, err := tag.getVarint()
+	if err != nil {
+		return nil, err
+	}
+	if varint {
+		return nil, errors.New("'as' cannot be combined with 'varint' or 'zigzag'")
+	}
+	if wire.IsSigned() && bt.Kind() != types.Int {
+		return nil, fmt.Errorf("unsigned %s cannot be serialized as signed %s", name, wire.Name())
+	}
+	return domain.NewWireIntFieldType(name, bt.Name(), wire), nil
+}
+
+func getNamedStructFieldType(typ *types.Named, st *types.Struct, valueExpr string, trimPkgPath string, nesting []*types.Named) (domain.FieldType, error) {
+	if typ.Obj().Pkg() == nil || typ.Obj().Pkg().Path() != trimPkgPath {
+		return nil, errors.Join(domain.ErrUnsupportedType, fmt.Errorf("nested struct %v is not from the same package", typ))
+	}
+	if slices.Contains(nesting, typ) {
+		return nil, errors.Join(domain.ErrUnsupportedType, fmt.Errorf("recursive struct type %v", typ))
+	}
+	name, err := getTypeName(typ, trimPkgPath)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := analyzeFields(name, st, valueExpr, trimPkgPath, append(nesting, typ))
+	if err != nil {
+		return nil, err
+	}
+	return newNestedStructFieldType(name, fields)
+}
+
+// Nested structs are inlined into parent's code, so their fields must not depend on
+// nested struct's own fields, which excludes variable size.
+func newNestedStructFieldType(name string, fields []domain.StructField) (domain.FieldType, error) {
+	ft := domain.NewStructFieldType(name, fields)
+	if !domain.IsFixedSize(*ft) {
+		return nil, errors.Join(domain.ErrUnsupportedType, fmt.Errorf("nested struct %s has variable size", name))
+	}
+	return ft, nil
+}
+
+func isString(t types.Type) bool {
+	bt, ok := t.Underlying().(*types.Basic)
+	return ok && bt.Info()&types.IsString != 0
+}
+
+func isBool(t types.Type) bool {
+	bt, ok := t.Underlying().(*types.Basic)
+	return ok && bt.Info()&types.IsBoolean != 0
+}
+
+// Default max length of NUL-terminated strings, if not set with 'max' tag attribute
+const defaultCStrMaxLen = 4096
+
+func getStringFieldType(t types.Type, valueExpr string, trimPkgPath string, tag *structTag) (domain.FieldType, error) {
+	name, err := getTypeName(t, trimPkgPath)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := tag.getStringEncoding()
+	if err != nil {
+		return nil, err
+	}
+	if valueExpr == "" && enc != domain.StringFixed {
+		return nil, errors.Join(domain.ErrUnsupportedType, errors.New("only fixed strings are supported as sequence elements"))
+	}
+
+	maxLen, hasMaxLen, err := tag.getMaxLen()
+	if err != nil {
+		return nil, err
+	}
+
+	switch enc {
+	case domain.StringLenExpr:
+		lenExpr, _, err := tag.getLenExpr()
+		if err != nil {
+			return nil, err
+		}
+		if lenExpr == "" {
+			return nil, errors.Join(domain.ErrUnsupportedType, errors.New("empty length expression"))
+		}
+		return domain.NewLenExprStringFieldType(name, lenExpr, maxLen), nil
+
+	case domain.StringPrefixed:
+		prefix, err := tag.getLenPrefix()
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewPrefixedStringFieldType(name, valueExpr, prefix, maxLen), nil
+
+	case domain.StringFixed:
+		size, err := tag.getPositiveInt("fixed")
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewFixedStringFieldType(name, size), nil
+
+	default:
+		if !hasMaxLen {
+			maxLen = defaultCStrMaxLen
+		}
+		return domain.NewNulTerminatedStringFieldType(name, valueExpr, maxLen), nil
+	}
+}
+
 func getTypeName(t types.Type, trimPkgPath string) (name string, err error) {
 	trimPkgPath = trimPkgPath + "."
 
@@ -151,11 +816,26 @@ func getTypeName(t types.Type, trimPkgPath string) (name string, err error) {
 		name = strings.TrimPrefix(name, trimPkgPath)
 		return name, nil
 
+	case *types.Struct:
+		// Type literal, with local package qualifier omitted
+		name = types.TypeString(typ, localQualifier(strings.TrimSuffix(trimPkgPath, ".")))
+		return name, nil
+
 	default:
 		return "", fmt.Errorf("failed to get name and size for %T", typ)
 	}
 }
 
+// Qualifies types of other packages by package name, and omits qualifier of the local package pkgPath.
+func localQualifier(pkgPath string) types.Qualifier {
+	return func(p *types.Package) string {
+		if p.Path() == pkgPath {
+			return ""
+		}
+		return p.Name()
+	}
+}
+
 func getTypeSize(t types.Type) (int, error) {
 	tOrig := t
 	for t != t.Underlying() {
@@ -174,8 +854,9 @@ func getTypeSize(t types.Type) (int, error) {
 		return 4, nil
 	case types.Int64, types.Uint64, types.Float64:
 		return 8, nil
-	case types.Int, types.Uint:
-		return 0, fmt.Errorf("%v: type size differs based on platform, please choose exact-sized one", t)
+	case types.Int, types.Uint, types.Uintptr:
+		return 0, fmt.Errorf("%v: type size differs based on platform, please choose exact-sized one, "+
+			"or set serialized type with 'as' tag, e.g. `simser:\"as=int32\"`", t)
 	default:
 		return 0, fmt.Errorf("type not found, %v", t)
 	}
@@ -0,0 +1,198 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+// Type-checks src and analyzes fields of its struct T
+func analyzeSource(t *testing.T, src string) error {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := goparser.ParseFile(fset, "t.go", "package p\n"+src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := (&types.Config{}).Check("example.com/p", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := pkg.Scope().Lookup("T").Type().Underlying().(*types.Struct)
+	_, err = analyzeFields("T", st, "o", pkg.Path(), nil)
+	return err
+}
+
+func TestLenOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{
+			name: "plain",
+			src: "type T struct {\n" +
+				"\tCount uint16 `simser:\"lenof=Items\"`\n" +
+				"\tItems []uint32 `simser:\"len=o.Count\"`\n}",
+		},
+		{
+			name: "int conversion",
+			src: "type T struct {\n" +
+				"\tCount uint16 `simser:\"lenof=Items\"`\n" +
+				"\tItems []uint32 `simser:\"len=int(o.Count)\"`\n}",
+		},
+		{
+			name: "derived length",
+			src: "type T struct {\n" +
+				"\tCount uint16 `simser:\"lenof=Items\"`\n" +
+				"\tItems []uint32 `simser:\"len=int(o.Count)-1\"`\n}",
+			wantErr: "field 'T.Count': 'lenof' refers to 'Items', whose length expression 'int(o.Count)-1' is not",
+		},
+		{
+			name: "other field",
+			src: "type T struct {\n" +
+				"\tCount uint16 `simser:\"lenof=Items\"`\n" +
+				"\tN     uint16\n" +
+				"\tItems []uint32 `simser:\"len=int(o.N)\"`\n}",
+			wantErr: "field 'T.Count': 'lenof' refers to 'Items', whose length expression 'int(o.N)' is not",
+		},
+		{
+			name: "not a slice",
+			src: "type T struct {\n" +
+				"\tCount uint16 `simser:\"lenof=N\"`\n" +
+				"\tN     uint16\n}",
+			wantErr: "which is not a slice or 'len' string field",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := analyzeSource(t, tt.src)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTagExprNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		wantErr string
+	}{
+		{name: "field", field: "S []byte `simser:\"len=int(o.N)\"`"},
+		{name: "package const", field: "S []byte `simser:\"len=int(o.N)*Size\"`"},
+		{name: "predeclared", field: "S uint8 `simser:\"if=o.N > 0 && true\"`"},
+		{name: "selector", field: "S uint8 `simser:\"if=o.N > 0 && o.N.b\"`"},
+		{
+			name:    "generated local",
+			field:   "S []byte `simser:\"len=int(toRead)\"`",
+			wantErr: "'len' expression 'int(toRead)' refers to 'toRead', which is a variable of generated code",
+		},
+		{
+			name:    "shadowed package var",
+			field:   "S uint8 `simser:\"if=p > 0\"`",
+			wantErr: "'if' expression 'p > 0' refers to 'p', which is a variable of generated code",
+		},
+		{
+			name:    "loop var",
+			field:   "S []byte `simser:\"len=i3\"`",
+			wantErr: "refers to 'i3', which is a variable of generated code",
+		},
+		{
+			name:    "unknown",
+			field:   "S uint8 `simser:\"if=x > 0\"`",
+			wantErr: "'if' expression 'x > 0' refers to 'x', which is neither 'o' nor a package-level name",
+		},
+		{
+			name:    "invalid",
+			field:   "S uint8 `simser:\"if=o.N >\"`",
+			wantErr: "invalid 'if' expression 'o.N >'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "const Size = 2\n\nvar p = 1\n\ntype T struct {\n\tN uint8\n\t" + tt.field + "\n}"
+			err := analyzeSource(t, src)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBitContainer(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  string
+		wantErr string
+	}{
+		{
+			name:   "explicit",
+			fields: "A uint8 `simser:\"bits=4/16\"`\n\tB uint8 `simser:\"bits=4\"`\n\tC uint8 `simser:\"bits=8\"`",
+		},
+		{
+			name:    "overflow",
+			fields:  "A uint8 `simser:\"bits=4/8\"`\n\tB uint8 `simser:\"bits=8\"`",
+			wantErr: "bit fields take 12 bits, which is more than their container of 8 bits",
+		},
+		{
+			name:    "not filled",
+			fields:  "A uint8 `simser:\"bits=4/16\"`\n\tB uint8 `simser:\"bits=4\"`",
+			wantErr: "take 8 bits, which doesn't fill their container of 16 bits",
+		},
+		{
+			name:    "not first",
+			fields:  "A uint8 `simser:\"bits=4\"`\n\tB uint8 `simser:\"bits=4/16\"`",
+			wantErr: "container size of bit fields must be set on the first field of the container",
+		},
+		{
+			name:    "invalid size",
+			fields:  "A uint8 `simser:\"bits=4/12\"`",
+			wantErr: "invalid container size in 'bits' value '4/12'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := analyzeSource(t, "type T struct {\n\t"+tt.fields+"\n}")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -17,11 +17,12 @@ package parser
 import (
 	"errors"
 	"fmt"
-	"go/ast"
 	"go/token"
 	"reflect"
 	"strconv"
 	"strings"
+
+	"github.com/amanofbits/simser/internal/domain"
 )
 
 type structTag struct {
@@ -39,19 +40,10 @@ func newStructTag() *structTag {
 // 	return val, ok
 // }
 
-func (p *structTag) parse(t *ast.BasicLit) error {
+// Parses unquoted struct tag, as returned by types.Struct.Tag
+func (p *structTag) parse(rawTag string) error {
 	p.values = map[string]string{}
-	if t == nil {
-		return nil
-	}
-	if t.Kind != token.STRING {
-		return fmt.Errorf("illegal tag kind %s", t.Kind)
-	}
-	unqTag, err := strconv.Unquote(t.Value)
-	if err != nil {
-		return err
-	}
-	tag := reflect.StructTag(unqTag) // reflect has "canonical" parser for tags
+	tag := reflect.StructTag(rawTag) // reflect has "canonical" parser for tags
 
 	simser, ok := tag.Lookup("simser")
 	if !ok {
@@ -63,6 +55,7 @@ func (p *structTag) parse(t *ast.BasicLit) error {
 		idx := strings.Index(v, "=")
 		if idx < 0 {
 			p.values[v] = ""
+			continue
 		}
 		p.values[v[:idx]] = v[idx+1:]
 	}
@@ -78,6 +71,233 @@ func (p structTag) getLenExpr() (expr string, ok bool, err error) {
 	return expr, ok, p._validateExpr(key, expr)
 }
 
+// Returns condition expression, under which the field is [de]serialized
+func (p structTag) getCondExpr() (expr string, ok bool, err error) {
+	key := "if"
+	expr, ok = p.values[key]
+	if !ok {
+		return expr, ok, nil
+	}
+	if strings.TrimSpace(expr) == "" {
+		return expr, ok, fmt.Errorf("'%s' expression is empty", key)
+	}
+	return expr, ok, p._validateExpr(key, expr)
+}
+
+// Returns raw constant value of the field
+func (p structTag) getConst() (val string, ok bool) {
+	val, ok = p.values["const"]
+	return strings.TrimSpace(val), ok
+}
+
+func (p structTag) getByteOrder() (order domain.ByteOrder, err error) {
+	val, ok := p.values["order"]
+	if !ok {
+		return domain.DefaultByteOrder, nil
+	}
+	return domain.ParseByteOrder(val)
+}
+
+// Returns true if the field must be excluded from [de]serialization entirely
+func (p structTag) isSkipped() bool {
+	_, ok := p.values["skip"]
+	return ok
+}
+
+func (p structTag) getSkipMode() (mode domain.SkipMode) {
+	if _, ok := p.values["rskip"]; ok {
+		mode |= domain.SkipRead
+	}
+	if _, ok := p.values["wskip"]; ok {
+		mode |= domain.SkipWrite
+	}
+	return mode
+}
+
+// Returns the byte used to fill padding and write-skipped fields, 0 by default
+func (p structTag) getPadByte() (b byte, err error) {
+	val, ok := p.values["pad"]
+	if !ok {
+		return 0, nil
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(val), 0, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid pad byte '%s', %w", val, err)
+	}
+	return byte(v), nil
+}
+
+// Returns a copy of the tag without the key
+func (p structTag) without(key string) *structTag {
+	c := &structTag{values: make(map[string]string, len(p.values))}
+	for k, v := range p.values {
+		if k != key {
+			c.values[k] = v
+		}
+	}
+	return c
+}
+
+// Returns string encoding, selected with one of 'len', 'prefix', 'fixed' or 'cstr' keys
+func (p structTag) getStringEncoding() (enc domain.StringEncoding, err error) {
+	keys := []struct {
+		key string
+		enc domain.StringEncoding
+	}{
+		{"len", domain.StringLenExpr},
+		{"prefix", domain.StringPrefixed},
+		{"fixed", domain.StringFixed},
+		{"cstr", domain.StringNulTerminated},
+	}
+	found := ""
+	for _, k := range keys {
+		if _, ok := p.values[k.key]; !ok {
+			continue
+		}
+		if found != "" {
+			return enc, fmt.Errorf("ambiguous string encoding, both '%s' and '%s' are set", found, k.key)
+		}
+		found, enc = k.key, k.enc
+	}
+	if found == "" {
+		return enc, errors.Join(domain.ErrUnsupportedType,
+			errors.New("string length cannot be determined. use one of 'len', 'prefix', 'fixed' or 'cstr' tag attributes"))
+	}
+	return enc, nil
+}
+
+// Returns the type of string length prefix: u8, u16, u32, u64 or varint
+func (p structTag) getLenPrefix() (domain.FieldType, error) {
+	val := strings.TrimSpace(p.values["prefix"])
+	switch val {
+	case "varint":
+		return domain.NewVarintFieldType(domain.NewSimpleFieldType("uint64", 8, nil), false, ""), nil
+	case "u8":
+		return domain.NewSimpleFieldType("uint8", 1, nil), nil
+	case "u16":
+		return domain.NewSimpleFieldType("uint16", 2, nil), nil
+	case "u32":
+		return domain.NewSimpleFieldType("uint32", 4, nil), nil
+	case "u64":
+		return domain.NewSimpleFieldType("uint64", 8, nil), nil
+	default:
+		return nil, fmt.Errorf("invalid length prefix '%s', expected one of u8, u16, u32, u64, varint", val)
+	}
+}
+
+// Returns name of the codec, whose functions [de]serialize the field
+func (p structTag) getCodec() (codec string, ok bool, err error) {
+	val, ok := p.values["codec"]
+	if !ok {
+		return "", false, nil
+	}
+	codec = strings.TrimSpace(val)
+	if !token.IsIdentifier(codec) {
+		return "", false, fmt.Errorf("invalid codec name '%s', expected identifier", val)
+	}
+	return codec, true, nil
+}
+
+// Returns fixed-size integer type, that platform-sized integer is serialized as.
+// Short names, like for length prefix, are accepted as well.
+func (p structTag) getWireType() (wire *domain.SimpleFieldType, ok bool, err error) {
+	val, ok := p.values["as"]
+	if !ok {
+		return nil, false, nil
+	}
+	name := strings.TrimSpace(val)
+	if strings.HasPrefix(name, "i") && !strings.HasPrefix(name, "int") {
+		name = "int" + name[1:]
+	} else if strings.HasPrefix(name, "u") && !strings.HasPrefix(name, "uint") {
+		name = "uint" + name[1:]
+	}
+	switch name {
+	case "int8", "uint8":
+		return domain.NewSimpleFieldType(name, 1, nil), true, nil
+	case "int16", "uint16":
+		return domain.NewSimpleFieldType(name, 2, nil), true, nil
+	case "int32", "uint32":
+		return domain.NewSimpleFieldType(name, 4, nil), true, nil
+	case "int64", "uint64":
+		return domain.NewSimpleFieldType(name, 8, nil), true, nil
+	default:
+		return nil, false, fmt.Errorf("invalid wire type '%s', expected one of int8..int64, uint8..uint64, or i8..i64, u8..u64", val)
+	}
+}
+
+func (p structTag) getMaxLen() (maxLen int, ok bool, err error) {
+	if _, ok := p.values["max"]; !ok {
+		return 0, false, nil
+	}
+	maxLen, err = p.getPositiveInt("max")
+	return maxLen, err == nil, err
+}
+
+func (p structTag) getPositiveInt(key string) (int, error) {
+	val := strings.TrimSpace(p.values[key])
+	v, err := strconv.ParseInt(val, 0, 0)
+	if err != nil {
+		return 0, fmt.Errorf("invalid '%s' value '%s', %w", key, val, err)
+	}
+	if v < 1 {
+		return 0, fmt.Errorf("'%s' value must be positive, got %d", key, v)
+	}
+	return int(v), nil
+}
+
+// Returns the name of a field, whose length is held by tagged field
+func (p structTag) getLenOf() (name string, ok bool) {
+	name, ok = p.values["lenof"]
+	return strings.TrimSpace(name), ok
+}
+
+// Returns true if integer is serialized as varint, selected with 'varint' (unsigned LEB128)
+// or 'zigzag' (zigzag-encoded signed integer) keys
+func (p structTag) getVarint() (varint, zigzag bool, err error) {
+	_, varint = p.values["varint"]
+	_, zigzag = p.values["zigzag"]
+	if varint && zigzag {
+		return false, false, errors.New("ambiguous integer encoding, both 'varint' and 'zigzag' are set")
+	}
+	return varint || zigzag, zigzag, nil
+}
+
+// Returns number of bits, taken by a bit field
+// Returns the number of bits of a bit field, and the size of its container in bits, if it is set
+// as 'bits=4/16'. Container size is 0 if it is not set.
+func (p structTag) getBits() (bits, container int, ok bool, err error) {
+	val, ok := p.values["bits"]
+	if !ok {
+		return 0, 0, false, nil
+	}
+	if idx := strings.Index(val, "/"); idx >= 0 {
+		c, err := strconv.ParseInt(strings.TrimSpace(val[idx+1:]), 0, 0)
+		if err != nil || (c != 8 && c != 16 && c != 32 && c != 64) {
+			return 0, 0, false, fmt.Errorf("invalid container size in 'bits' value '%s', must be 8, 16, 32 or 64", val)
+		}
+		container = int(c)
+		p = structTag{values: map[string]string{"bits": val[:idx]}}
+	}
+	bits, err = p.getPositiveInt("bits")
+	return bits, container, err == nil, err
+}
+
+// Returns order of bit fields in their container
+func (p structTag) getBitOrder() (order domain.BitOrder, ok bool, err error) {
+	val, ok := p.values["bitorder"]
+	if !ok {
+		return domain.MSBFirst, false, nil
+	}
+	order, err = domain.ParseBitOrder(val)
+	return order, err == nil, err
+}
+
+// Returns true if only canonical values are accepted on read, e.g. 0 and 1 for bool
+func (p structTag) isStrict() bool {
+	_, ok := p.values["strict"]
+	return ok
+}
+
 var ErrCommentInTagExpr = errors.New("comments are not allowed within tag expressions")
 
 func (p structTag) _validateExpr(key, expr string) error {
@@ -15,103 +15,467 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"flag"
 	"fmt"
+	"go/token"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/amanofbits/simser/internal/diff"
+	"github.com/amanofbits/simser/internal/domain"
 	"github.com/amanofbits/simser/internal/generator"
 	myParser "github.com/amanofbits/simser/internal/parser"
+	"golang.org/x/tools/go/packages"
 )
 
 type config struct {
-	targetFile  string
-	rawTypes    string
-	outputFile  string
-	readFnName  string
-	writeFnName string
+	targetFile    string // Empty in package mode
+	targetDir     string // Package directory, in package mode
+	fromGoGen     bool   // Target is taken from go generate environment
+	pkgMode       bool
+	perFile       bool // Write output per source file, in package mode
+	rawTypes      string
+	outputFile    string
+	readFnName    string // Empty if reader must not be generated
+	writeFnName   string // Empty if writer must not be generated
+	byteOrder     domain.ByteOrder
+	stdInterfaces bool
+	byteSlices    bool
+	maxAlloc      int      // Max bytes allocated for a single slice or string on read, 0 if not limited
+	check         bool     // Compare output with existing files, instead of writing
+	stdout        bool     // Print output, instead of writing
+	headerArgs    []string // Arguments, written to header of generated code
 }
 
-func getConfig() (c config, err error) {
+// Flag that selects function generation, and can be used as boolean (-r)
+// or with custom function name (-r=Name).
+type fnFlag struct {
+	isSet bool
+	name  string
+}
+
+func (f *fnFlag) String() string   { return f.name }
+func (f *fnFlag) IsBoolFlag() bool { return true }
+
+func (f *fnFlag) Set(s string) error {
+	switch s {
+	case "true":
+		f.isSet = true
+	case "false":
+		f.isSet = false
+	default:
+		if !token.IsIdentifier(s) {
+			return fmt.Errorf("'%s' is not a valid function name", s)
+		}
+		f.isSet, f.name = true, s
+	}
+	return nil
+}
+
+func getConfig(args []string) (c config, err error) {
+	fs := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ExitOnError)
+
+	fs.StringVar(&c.rawTypes, "types", "", "comma-separated struct types to use")
+	fs.StringVar(&c.outputFile, "output", "", "name of output file")
+	fs.StringVar(&c.readFnName, "read-fn-name", "LoadFrom", "name of deserializing (read) function")
+	fs.StringVar(&c.writeFnName, "write-fn-name", "SaveTo", "name of serializing (write) function")
+	rawByteOrder := fs.String("byte-order", "le", "byte order of serialized data, 'le' or 'be'")
+	var readFlag, writeFlag fnFlag
+	fs.Var(&readFlag, "r", "generate deserializing (read) function, optionally with custom name (-r=Name)")
+	fs.Var(&writeFlag, "w", "generate serializing (write) function, optionally with custom name (-w=Name)")
+	fs.BoolVar(&c.byteSlices, "bytes", false, "additionally generate DecodeFrom, MarshalTo and AppendBinary methods, working with byte slices")
+	fs.IntVar(&c.maxAlloc, "max-alloc", 0, "max size in bytes of a single slice or string, allocated on read. 0 means no limit")
+	rawInterfaces := fs.String("interfaces", "", "additional interfaces to implement. 'std' for io.ReaderFrom, io.WriterTo, encoding.BinaryMarshaler and encoding.BinaryUnmarshaler")
+
+	fs.BoolVar(&c.pkgMode, "pkg", false, "process all files of the package in current directory, instead of GOFILE")
+	fs.BoolVar(&c.perFile, "per-file", false, "in package mode, write output per source file, instead of a single "+pkgOutputFile)
+	fs.BoolVar(&c.check, "check", false, "check that output files are up to date, and print diff if not, instead of writing them")
+	fs.BoolVar(&c.stdout, "stdout", false, "print generated code to stdout, instead of writing output files")
+	fileFlag := fs.String("file", "", "target file, instead of GOFILE")
+	dirFlag := fs.String("dir", "", "target package directory, implies -pkg")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [flags] [file.go | dir]\n", fs.Name())
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if err := c.setTarget(*fileFlag, *dirFlag, fs.Args()); err != nil {
+		return c, err
+	}
+	c.headerArgs = outputArgs(args[:len(args)-fs.NArg()])
+	if c.check && c.stdout {
+		return c, fmt.Errorf("-check cannot be used with -stdout")
+	}
+	if c.maxAlloc < 0 {
+		return c, fmt.Errorf("-max-alloc must not be negative, got %d", c.maxAlloc)
+	}
 
-	c.targetFile = os.Getenv("GOFILE")
-	c.targetFile, err = filepath.Abs(c.targetFile)
+	// Both functions are generated, unless any of them is selected explicitly
+	if readFlag.isSet || writeFlag.isSet {
+		c.readFnName = selectFnName(readFlag, c.readFnName)
+		c.writeFnName = selectFnName(writeFlag, c.writeFnName)
+	}
+
+	c.byteOrder, err = domain.ParseByteOrder(*rawByteOrder)
 	if err != nil {
 		return c, err
 	}
-	log.Printf("Target file: %s", c.targetFile)
+
+	switch *rawInterfaces {
+	case "":
+	case "std":
+		c.stdInterfaces = true
+		if err := c.checkFnNames(generator.StdInterfaceMethods, "-interfaces=std methods"); err != nil {
+			return c, err
+		}
+	default:
+		return c, fmt.Errorf("unknown interfaces '%s', expected 'std'", *rawInterfaces)
+	}
+	if c.byteSlices {
+		if err := c.checkFnNames(generator.ByteSliceMethods, "-bytes methods"); err != nil {
+			return c, err
+		}
+	}
+	if err := c.checkFnNames([]string{generator.EncodedSizeMethod}, "size method"); err != nil {
+		return c, err
+	}
+
+	switch {
+	case c.pkgMode && c.perFile:
+		if c.outputFile != "" {
+			return c, fmt.Errorf("-output cannot be used with -per-file")
+		}
+	case c.pkgMode:
+		if c.outputFile == "" {
+			c.outputFile = filepath.Join(c.targetDir, pkgOutputFile)
+		}
+	default:
+		if c.outputFile == "" {
+			c.outputFile = fileOutputName(c.targetFile)
+		}
+	}
+
+	return c, nil
+}
+
+// Output file name in package mode, if output is not written per source file.
+const pkgOutputFile = "simser_gen.go"
+
+// Name of output file for source file.
+func fileOutputName(sourceFile string) string {
+	return fmt.Sprintf("%s.simser.go", strings.TrimSuffix(sourceFile, ".go"))
+}
+
+// Selects target file or package directory, from flags, positional argument or go generate environment.
+func (c *config) setTarget(file, dir string, args []string) error {
+	switch len(args) {
+	case 0:
+	case 1:
+		if file != "" || dir != "" {
+			return fmt.Errorf("positional argument cannot be used with -file or -dir")
+		}
+		fi, err := os.Stat(args[0])
+		if err != nil {
+			return fmt.Errorf("target error, %w", err)
+		}
+		if fi.IsDir() {
+			dir = args[0]
+		} else {
+			file = args[0]
+		}
+	default:
+		return fmt.Errorf("expected at most one target, got %d", len(args))
+	}
+
+	if file != "" && (dir != "" || c.pkgMode) {
+		return fmt.Errorf("target file cannot be used with -dir or -pkg")
+	}
+	if dir != "" || c.pkgMode {
+		c.pkgMode = true
+		if dir == "" {
+			// go generate runs commands in the package directory
+			dir, c.fromGoGen = ".", true
+		}
+		return c.setTargetDir(dir)
+	}
+
+	if c.perFile {
+		return fmt.Errorf("-per-file can only be used with -pkg or -dir")
+	}
+	c.fromGoGen = file == ""
+	if c.fromGoGen {
+		file = os.Getenv("GOFILE")
+		if file == "" {
+			return fmt.Errorf("no target: GOFILE is not set, and neither -file, -dir nor positional argument is given")
+		}
+	}
+	if err := c.setTargetFile(file); err != nil {
+		return err
+	}
+	if line := os.Getenv("GOLINE"); c.fromGoGen && line != "" {
+		log.Printf("Target file: %s, directive at line %s", c.targetFile, line)
+	} else {
+		log.Printf("Target file: %s", c.targetFile)
+	}
+	return nil
+}
+
+func (c *config) setTargetFile(file string) (err error) {
+	c.targetFile, err = filepath.Abs(file)
+	if err != nil {
+		return err
+	}
 
 	fi, err := os.Stat(c.targetFile)
 	if err != nil {
-		return c, fmt.Errorf("target file error, %w", err)
+		return fmt.Errorf("target file error, %w", err)
 	}
 	if !fi.Mode().IsRegular() {
-		return c, fmt.Errorf("target file is not a regular file")
+		return fmt.Errorf("target file is not a regular file")
 	}
+	return nil
+}
 
-	flag.StringVar(&c.rawTypes, "types", "", "comma-separated struct types to use")
-	flag.StringVar(&c.outputFile, "output", "", "name of output file")
-	flag.StringVar(&c.readFnName, "read-fn-name", "LoadFrom", "name of deserializing (read) function")
-	flag.StringVar(&c.writeFnName, "write-fn-name", "SaveTo", "name of serializing (write) function")
+func (c *config) setTargetDir(dir string) (err error) {
+	c.targetDir, err = filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
 
-	flag.Parse()
+	fi, err := os.Stat(c.targetDir)
+	if err != nil {
+		return fmt.Errorf("target directory error, %w", err)
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("target %s is not a directory", c.targetDir)
+	}
+	log.Printf("Target package: %s", c.targetDir)
+	return nil
+}
 
-	if c.outputFile == "" {
-		c.outputFile = fmt.Sprintf("%s.simser.go", strings.TrimSuffix(c.targetFile, ".go"))
+// Returns error if package name is not the one go generate runs for.
+func checkGoPackage(name string) error {
+	if goPkg := os.Getenv("GOPACKAGE"); goPkg != "" && goPkg != name {
+		return fmt.Errorf("GOPACKAGE is %s, but loaded package is %s. Test packages are not supported", goPkg, name)
 	}
+	return nil
+}
 
-	return c, nil
+// Returns error if read or write function name is one of reserved names.
+func (c config) checkFnNames(reserved []string, what string) error {
+	for _, name := range reserved {
+		if c.readFnName == name || c.writeFnName == name {
+			return fmt.Errorf("function name %s conflicts with %s", name, what)
+		}
+	}
+	return nil
+}
+
+func selectFnName(f fnFlag, defaultName string) string {
+	if !f.isSet {
+		return ""
+	}
+	if f.name != "" {
+		return f.name
+	}
+	return defaultName
 }
 
 func main() {
-	cfg, err := getConfig()
-	if err != nil {
+	if err := run(os.Args[1:]); err != nil {
 		log.Fatal(err)
 	}
+}
+
+func run(args []string) error {
+	cfg, err := getConfig(args)
+	if err != nil {
+		return err
+	}
 
 	acceptor, err := newTypeAcceptor(strings.Split(cfg.rawTypes, ","))
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+
+	if cfg.pkgMode {
+		return processPackage(cfg, acceptor)
 	}
+	return processFile(cfg, acceptor)
+}
 
+func processFile(cfg config, acceptor *typeAcceptor) error {
 	file, err := myParser.Parse(cfg.targetFile)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	if cfg.fromGoGen {
+		if err := checkGoPackage(file.Pkg.Name); err != nil {
+			return err
+		}
 	}
 
 	inputStructs, err := file.GetInputStructs(acceptor)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+
+	output, err := genOutput(file.Pkg, inputStructs, cfg.outputFile, cfg)
+	if err != nil {
+		return err
+	}
+	return emitOutput(cfg, output, cfg.outputFile)
+}
+
+func processPackage(cfg config, acceptor *typeAcceptor) error {
+	pkg, err := myParser.ParsePackage(cfg.targetDir)
+	if err != nil {
+		return err
+	}
+	if cfg.fromGoGen {
+		if err := checkGoPackage(pkg.Pkg.Name); err != nil {
+			return err
+		}
+	}
+
+	fileStructs, err := pkg.GetInputStructs(acceptor)
+	if err != nil {
+		return err
+	}
+
+	if !cfg.perFile {
+		inputStructs := []domain.InputStruct{}
+		for _, fs := range fileStructs {
+			inputStructs = append(inputStructs, fs.Structs...)
+		}
+		output, err := genOutput(pkg.Pkg, inputStructs, cfg.outputFile, cfg)
+		if err != nil {
+			return err
+		}
+		return emitOutput(cfg, output, cfg.outputFile)
 	}
 
-	output := generator.NewOutput(file.Pkg)
+	stale := false
+	for _, fs := range fileStructs {
+		filename := fileOutputName(fs.File.Path)
+		output, err := genOutput(pkg.Pkg, fs.Structs, filename, cfg)
+		if err != nil {
+			return err
+		}
+		// All files are checked, to show all diffs at once
+		err = emitOutput(cfg, output, filename)
+		if errors.Is(err, errStale) {
+			stale = true
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if stale {
+		return errStale
+	}
+	return nil
+}
+
+func genOutput(pkg *packages.Package, inputStructs []domain.InputStruct, filename string, cfg config) (*generator.Output, error) {
+	output := generator.NewOutput(pkg, filename, cfg.headerArgs)
 
 	for _, s := range inputStructs {
 		log.Printf("Processing %s...", s.Name())
-		if err := generator.GenStructCode(s, output, cfg.readFnName, cfg.writeFnName); err != nil {
-			log.Fatal(err)
+		if err := generator.GenStructCode(s, output, generator.Options{
+			ReadFnName:       cfg.readFnName,
+			WriteFnName:      cfg.writeFnName,
+			ByteOrder:        cfg.byteOrder,
+			StdInterfaces:    cfg.stdInterfaces,
+			ByteSliceMethods: cfg.byteSlices,
+			MaxAlloc:         cfg.maxAlloc,
+		}); err != nil {
+			return nil, err
 		}
 		log.Print("Done.")
 	}
+	return output, nil
+}
 
-	if err := writeOutputFile(output, cfg.outputFile); err != nil {
-		log.Fatal(err)
+// Error, returned if generated code differs from output file in check mode.
+var errStale = errors.New("generated code is out of date")
+
+// Writes output to file, or prints it, or compares it to the file, depending on the mode.
+func emitOutput(cfg config, output *generator.Output, filename string) error {
+	buf := bytes.Buffer{}
+	if _, err := output.WriteTo(&buf); err != nil {
+		return fmt.Errorf("failed to generate code, %w", err)
+	}
+
+	switch {
+	case cfg.stdout:
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	case cfg.check:
+		return checkOutputFile(buf.Bytes(), filename)
+	}
+	return writeOutputFile(buf.Bytes(), filename)
+}
+
+func writeOutputFile(code []byte, filename string) error {
+	if err := os.WriteFile(filename, code, 0o644); err != nil {
+		return fmt.Errorf("failed to write output file, %w", err)
 	}
+	log.Printf("Target file written: %s", filename)
+	return nil
 }
 
-func writeOutputFile(output *generator.Output, filename string) error {
+// Prints diff and returns errStale if file content differs from code.
+func checkOutputFile(code []byte, filename string) error {
+	old, err := os.ReadFile(filename)
+	oldName := filename
+	if errors.Is(err, os.ErrNotExist) {
+		oldName = os.DevNull
+	} else if err != nil {
+		return fmt.Errorf("failed to read output file, %w", err)
+	}
 
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create output file, %w", err)
+	d := diff.Unified(oldName, filename, old, code)
+	if d == "" {
+		log.Printf("Target file is up to date: %s", filename)
+		return nil
 	}
-	defer file.Close()
+	fmt.Print(d)
+	log.Printf("Target file is out of date: %s", filename)
+	return errStale
+}
 
-	if _, err := output.WriteTo(file); err != nil {
-		return fmt.Errorf("failed to write outpput file, %w", err)
+// Returns flag arguments, that affect generated code.
+// Output mode, target selection and output file are left out, so that output is the same for any invocation form
+// and working directory, e.g. under go generate and with -check -file.
+func outputArgs(flagArgs []string) []string {
+	res := make([]string, 0, len(flagArgs))
+	for i := 0; i < len(flagArgs); i++ {
+		arg := flagArgs[i]
+		if arg == "--" {
+			continue
+		}
+		if !strings.HasPrefix(arg, "-") {
+			res = append(res, arg)
+			continue
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		switch name {
+		case "check", "stdout", "pkg":
+		case "file", "dir", "output":
+			if !hasValue {
+				// Value is the next argument
+				i++
+			}
+		default:
+			res = append(res, arg)
+		}
 	}
-	log.Print("Target file written")
-	return nil
+	return res
 }
@@ -0,0 +1,235 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Packages with generated code, used as targets. Their output is generated by go generate.
+const (
+	e2eDir      = "internal/e2e"
+	fileModeDir = "internal/e2e/filemode"
+)
+
+// Changes working directory to dir, and returns a function that restores it
+func chdir(t *testing.T, dir string) (restore func()) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCheckInvocationForms(t *testing.T) {
+	fileModeFile := filepath.Join(fileModeDir, "types.go")
+	tests := []struct {
+		name string
+		args []string
+		want error
+	}{
+		{name: "dir", args: []string{"-check", "-dir", e2eDir, "-types=all", "-bytes", "-interfaces=std"}},
+		{name: "dir value", args: []string{"-check", "-dir=" + e2eDir, "-types=all", "-bytes", "-interfaces=std"}},
+		{name: "dir positional", args: []string{"-check", "-types=all", "-bytes", "-interfaces=std", e2eDir}},
+		{name: "file", args: []string{"-check", "-file", fileModeFile, "-types=Point"}},
+		{name: "file value", args: []string{"-check", "-file=" + fileModeFile, "-types=Point"}},
+		{name: "file positional", args: []string{"-check", "-types=Point", "--", fileModeFile}},
+		{name: "other flags", args: []string{"-check", "-file", fileModeFile, "-types=Point", "-bytes"}, want: errStale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := run(tt.args); !errors.Is(err, tt.want) {
+				t.Fatalf("run(%q) = %v, want %v", tt.args, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckGoGenerateOutput(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(t.TempDir(), "out.go")
+	// Output path, relative to working directory dir
+	relOutput := func(dir string) string {
+		rel, err := filepath.Rel(filepath.Join(wd, dir), output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rel
+	}
+
+	// As in go:generate directive of the file
+	func() {
+		defer chdir(t, fileModeDir)()
+		t.Setenv("GOFILE", "types.go")
+		t.Setenv("GOPACKAGE", "filemode")
+		if err := run([]string{"-types=Point", "-output", relOutput(fileModeDir)}); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	for _, dir := range []string{".", e2eDir} {
+		func() {
+			defer chdir(t, dir)()
+			target, err := filepath.Rel(filepath.Join(wd, dir), filepath.Join(wd, fileModeDir, "types.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			args := []string{"-check", "-file", target, "-types=Point", "-output=" + relOutput(dir)}
+			if err := run(args); err != nil {
+				t.Fatalf("in %s: run(%q) = %v", dir, args, err)
+			}
+		}()
+	}
+}
+
+func TestGoPackageCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		dir      string
+		goFile   string
+		pkgName  string
+		args     []string
+		explicit []string // Same target, set explicitly
+	}{
+		{
+			name:     "file",
+			dir:      fileModeDir,
+			goFile:   "types.go",
+			pkgName:  "filemode",
+			args:     []string{"-check", "-types=Point"},
+			explicit: []string{"-check", "-types=Point", filepath.Join(fileModeDir, "types.go")},
+		},
+		{
+			name:     "package",
+			dir:      e2eDir,
+			pkgName:  "e2e",
+			args:     []string{"-check", "-pkg", "-types=all", "-bytes", "-interfaces=std"},
+			explicit: []string{"-check", "-dir", e2eDir, "-types=all", "-bytes", "-interfaces=std"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GOFILE", tt.goFile)
+			t.Setenv("GOPACKAGE", tt.pkgName+"_test")
+			if err := run(tt.explicit); err != nil {
+				t.Fatalf("explicit target: %v", err)
+			}
+
+			defer chdir(t, tt.dir)()
+			err := run(tt.args)
+			if want := "GOPACKAGE is " + tt.pkgName + "_test, but loaded package is " + tt.pkgName; err == nil || !strings.Contains(err.Error(), want) {
+				t.Fatalf("from go generate: error = %v, want %q", err, want)
+			}
+			t.Setenv("GOPACKAGE", tt.pkgName)
+			if err := run(tt.args); err != nil {
+				t.Fatalf("from go generate: %v", err)
+			}
+		})
+	}
+}
+
+// Returns what f prints to stdout
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+	f()
+	w.Close()
+	return string(<-done)
+}
+
+func TestCheckOutputFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "out.go")
+	if err := os.WriteFile(filename, []byte("package p\n\nconst A = 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var err error
+	out := captureStdout(t, func() { err = checkOutputFile([]byte("package p\n\nconst A = 1\n"), filename) })
+	if err != nil || out != "" {
+		t.Fatalf("up to date: error = %v, output %q", err, out)
+	}
+
+	out = captureStdout(t, func() { err = checkOutputFile([]byte("package p\n\nconst A = 2\n"), filename) })
+	want := "--- " + filename + "\n+++ " + filename + "\n@@ -1,3 +1,3 @@\n package p\n \n-const A = 1\n+const A = 2\n"
+	if !errors.Is(err, errStale) || out != want {
+		t.Fatalf("stale: error = %v, output\n%s\nwant\n%s", err, out, want)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.go")
+	out = captureStdout(t, func() { err = checkOutputFile([]byte("package p\n"), missing) })
+	want = "--- " + os.DevNull + "\n+++ " + missing + "\n@@ -0,0 +1 @@\n+package p\n"
+	if !errors.Is(err, errStale) || out != want {
+		t.Fatalf("missing: error = %v, output\n%s\nwant\n%s", err, out, want)
+	}
+}
+
+func TestFnFlags(t *testing.T) {
+	target := filepath.Join(fileModeDir, "types.go")
+	tests := []struct {
+		args         []string
+		wantR, wantW string
+	}{
+		{args: nil, wantR: "LoadFrom", wantW: "SaveTo"},
+		{args: []string{"-r"}, wantR: "LoadFrom"},
+		{args: []string{"-w"}, wantW: "SaveTo"},
+		{args: []string{"-r", "-w"}, wantR: "LoadFrom", wantW: "SaveTo"},
+		{args: []string{"-r=Parse", "-w"}, wantR: "Parse", wantW: "SaveTo"},
+		{args: []string{"-w=Save", "-read-fn-name=Get"}, wantW: "Save"},
+		{args: []string{"-r", "-read-fn-name=Get", "-write-fn-name=Put"}, wantR: "Get"},
+		{args: []string{"-r=false", "-w"}, wantW: "SaveTo"},
+	}
+	for _, tt := range tests {
+		c, err := getConfig(append([]string{"-types=Point", "-file", target}, tt.args...))
+		if err != nil {
+			t.Fatalf("getConfig(%q): %v", tt.args, err)
+		}
+		if c.readFnName != tt.wantR || c.writeFnName != tt.wantW {
+			t.Errorf("getConfig(%q): read %q, write %q, want %q, %q", tt.args, c.readFnName, c.writeFnName, tt.wantR, tt.wantW)
+		}
+	}
+
+	var f fnFlag
+	if err := f.Set("1Parse"); err == nil {
+		t.Error("fnFlag.Set(1Parse): no error for invalid name")
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package simser contains types, used by generated code.
+package simser
+
+import "fmt"
+
+// ConstMismatchError is returned by generated readers, when a field with constant value
+// (e.g. a magic number) has different value in serialized data.
+type ConstMismatchError struct {
+	Type     string // Name of the struct type
+	Field    string // Name of the field
+	Offset   int    // Offset of the field in serialized data, in bytes
+	Expected any    // Constant value of the field
+	Actual   any    // Value found in serialized data
+}
+
+func (e *ConstMismatchError) Error() string {
+	return fmt.Sprintf("%s.%s at offset %d: expected %#v, got %#v", e.Type, e.Field, e.Offset, e.Expected, e.Actual)
+}
+
+// FieldError is returned by generated readers, when reading of a field fails.
+// It wraps the original error, e.g. io.ErrUnexpectedEOF.
+type FieldError struct {
+	Type   string // Name of the struct type
+	Field  string // Name of the field
+	Offset int    // Offset of the field in serialized data, in bytes
+	Err    error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s.%s at offset %d: %v", e.Type, e.Field, e.Offset, e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// LengthError is returned by generated readers, when length of a slice or string, found in serialized data
+// or computed from it, is negative or exceeds the limit, set with 'max' tag or -max-alloc flag.
+type LengthError struct {
+	Length int // Length of the field, in elements for slices and in bytes for strings
+	Max    int // Max allowed length, or -1 if length is not limited
+}
+
+func (e *LengthError) Error() string {
+	if e.Length < 0 || e.Max < 0 {
+		return fmt.Sprintf("invalid length %d", e.Length)
+	}
+	return fmt.Sprintf("length %d exceeds limit %d", e.Length, e.Max)
+}
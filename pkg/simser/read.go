@@ -0,0 +1,50 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simser
+
+import "io"
+
+// Max number of bytes ReadFull allocates ahead of data actually read.
+const readChunkSize = 64 << 10
+
+// ReadFull reads exactly size bytes from r into buf[:size], growing it if needed, and returns
+// the buffer, resliced to its capacity. Errors are the same as of io.ReadFull.
+// Unlike make followed by io.ReadFull, a big buffer is grown in chunks, as the data arrives,
+// so a hostile length cannot allocate much more memory than the data it is followed by.
+func ReadFull(r io.Reader, buf []byte, size int) ([]byte, int, error) {
+	if size <= cap(buf) {
+		buf = buf[:cap(buf)]
+		n, err := io.ReadFull(r, buf[:size])
+		return buf, n, err
+	}
+
+	buf = buf[:0]
+	for len(buf) < size {
+		chunk := size - len(buf)
+		if chunk > readChunkSize {
+			chunk = readChunkSize
+		}
+		buf = append(buf, make([]byte, chunk)...)
+		n, err := io.ReadFull(r, buf[len(buf)-chunk:])
+		if err != nil {
+			n += len(buf) - chunk
+			if err == io.EOF && n > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return buf[:cap(buf)], n, err
+		}
+	}
+	return buf[:cap(buf)], size, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 amanofbits
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simser
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrVarintOverflow is returned by generated readers, when a varint in serialized data
+// does not fit into the type of its field.
+var ErrVarintOverflow = errors.New("varint overflows field type")
+
+// Unsigned integer types, that can be serialized as varints.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Signed integer types, that can be serialized as zigzag varints.
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Uvarint decodes unsigned LEB128 integer of at most bits from the beginning of buf, as encoded by
+// binary.PutUvarint, and returns it with the number of bytes read.
+// If buf ends before the end of the integer, io.ErrUnexpectedEOF is returned.
+func Uvarint(buf []byte, bits int) (uint64, int, error) {
+	v, n := binary.Uvarint(buf)
+	if n == 0 {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	if n < 0 || (bits < 64 && v>>bits != 0) {
+		return 0, 0, ErrVarintOverflow
+	}
+	return v, n, nil
+}
+
+// Varint decodes zigzag-encoded signed integer of at most bits from the beginning of buf, as encoded by
+// binary.PutVarint. See Uvarint.
+func Varint(buf []byte, bits int) (int64, int, error) {
+	ux, n, err := Uvarint(buf, bits)
+	x := int64(ux >> 1)
+	if ux&1 != 0 {
+		x = ^x
+	}
+	return x, n, err
+}
+
+// ReadVarint reads bytes of a varint from r into buf one by one, as its length is not known in advance,
+// and returns the buffer, resliced to its capacity, and the number of bytes read.
+// Errors are the same as of io.ReadFull.
+func ReadVarint(r io.Reader, buf []byte) ([]byte, int, error) {
+	buf = buf[:cap(buf)]
+	for i := 0; i < binary.MaxVarintLen64; i++ {
+		if i == len(buf) {
+			buf = append(buf, 0)
+			buf = buf[:cap(buf)]
+		}
+		if _, err := io.ReadFull(r, buf[i:i+1]); err != nil {
+			if err == io.EOF && i > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return buf, i, err
+		}
+		if buf[i] < 0x80 {
+			return buf, i + 1, nil
+		}
+	}
+	return buf, binary.MaxVarintLen64, ErrVarintOverflow
+}
+
+// AppendUvarint appends v to dst, as encoded by binary.PutUvarint.
+func AppendUvarint(dst []byte, v uint64) []byte {
+	for v >= 0x80 {
+		dst = append(dst, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(dst, byte(v))
+}
+
+// AppendVarint appends v to dst, as encoded by binary.PutVarint.
+func AppendVarint(dst []byte, v int64) []byte {
+	return AppendUvarint(dst, zigzag(v))
+}
+
+// UvarintSize returns the number of bytes of v, encoded by AppendUvarint.
+func UvarintSize(v uint64) int {
+	n := 1
+	for v >= 0x80 {
+		v >>= 7
+		n++
+	}
+	return n
+}
+
+// VarintSize returns the number of bytes of v, encoded by AppendVarint.
+func VarintSize(v int64) int {
+	return UvarintSize(zigzag(v))
+}
+
+// UvarintSliceSize returns the number of bytes of all elements of s, encoded by AppendUvarint.
+func UvarintSliceSize[T Unsigned](s []T) int {
+	size := 0
+	for _, v := range s {
+		size += UvarintSize(uint64(v))
+	}
+	return size
+}
+
+// VarintSliceSize returns the number of bytes of all elements of s, encoded by AppendVarint.
+func VarintSliceSize[T Signed](s []T) int {
+	size := 0
+	for _, v := range s {
+		size += VarintSize(int64(v))
+	}
+	return size
+}
+
+func zigzag(v int64) uint64 {
+	ux := uint64(v) << 1
+	if v < 0 {
+		ux = ^ux
+	}
+	return ux
+}